@@ -0,0 +1,150 @@
+// aliases.go - alternative JSON keys for struct members
+// Copyright © 2016-2019 Charles Banning.  All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package checkjson
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// RewriteAliases returns the JSON object 'b' with the keys that are aliases
+// for members of 'val', which is of type struct, replaced by the members' JSON
+// names, so the JSON object can be decoded by encoding/json.  Aliases are
+// declared with the struct tag `checkjson:"alias=name1|name2"`; e.g.,
+//
+//	type Config struct {
+//		Timeout int `json:"timeout" checkjson:"alias=timeout_ms|timeoutMs"`
+//	}
+//
+// so that a JSON object written for an earlier version of the struct can
+// still be decoded.  Validate, UnknownJSONKeys and the other checks accept
+// aliases as they do the JSON names, and match them ignoring case; a JSON
+// name takes precedence over an alias.
+//
+// If a JSON object has more than one key for the same member and at least
+// one of them is an alias - e.g., "timeout" and "timeout_ms" - it isn't clear
+// which value is meant, so an *AliasError is returned.  Keys that aren't
+// decoded to any member are left as they are.
+func RewriteAliases(b []byte, val interface{}) ([]byte, error) {
+	n, err := parseObject(b)
+	if err != nil {
+		return nil, ResolveJSONError(b, err)
+	}
+	if err := rewriteAliases(n, reflect.ValueOf(val), nil); err != nil {
+		return nil, err
+	}
+	return n.appendJSON(nil), nil
+}
+
+// Unmarshal decodes the JSON object 'b' to 'val' using json.Unmarshal, after
+// replacing the keys that are aliases for members of 'val' - see
+// RewriteAliases.
+func Unmarshal(b []byte, val interface{}) error {
+	b, err := RewriteAliases(b, val)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, val)
+}
+
+// rewriteAliases replaces the alias keys in the JSON value 'n' for 'val'.
+// It follows 'val' as the walker does, but ignores anything but the keys.
+func rewriteAliases(n *node, val reflect.Value, p Path) error {
+	for val.IsValid() && !unmarshaler(val.Type()) {
+		if val.Kind() == reflect.Interface {
+			if val.IsNil() || val.Elem().Kind() != reflect.Ptr || val.Elem().IsNil() {
+				val, _ = interfaceValue(val.Type())
+			} else {
+				val = val.Elem()
+			}
+			continue
+		}
+		if val.Kind() != reflect.Ptr {
+			break
+		}
+		if val.Type().Elem() == val.Type() {
+			return nil // type P *P
+		}
+		if val.IsNil() {
+			val = reflect.New(val.Type().Elem())
+		}
+		val = val.Elem()
+	}
+	if !val.IsValid() || unmarshaler(val.Type()) {
+		return nil
+	}
+	typ := val.Type()
+
+	switch {
+	case n.kind == arrayKind && (typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array):
+		sval := reflect.New(typ.Elem()).Elem()
+		for i, e := range n.elems {
+			ev := sval
+			if i < val.Len() {
+				ev = val.Index(i)
+			}
+			if err := rewriteAliases(e, ev, p.elem(i)); err != nil {
+				return err
+			}
+		}
+	case n.kind == objectKind && typ.Kind() == reflect.Map:
+		mval := reflect.New(typ.Elem()).Elem()
+		for i, k := range n.keys {
+			if err := rewriteAliases(n.elems[i], mval, p.entry(k)); err != nil {
+				return err
+			}
+		}
+	case n.kind == objectKind && typ.Kind() == reflect.Struct:
+		sf := cachedTypeFields(typ)
+		keys := make(aliasKeys, len(sf.list))
+		for i, k := range n.keys {
+			j, name, _, ok := sf.lookup(k)
+			if !ok {
+				continue
+			}
+			f := &sf.list[j]
+			kp := p.child(k, f).at(n.kpos[i])
+			alias := name != f.jsonName()
+			if other, ok := keys.add(j, k, kp.Position(), alias); !ok {
+				return &AliasError{kp, typ, other}
+			}
+			if alias {
+				n.keys[i] = f.jsonName()
+			}
+			if f.ignored || f.norecurse || f.quoted {
+				continue
+			}
+			if err := rewriteAliases(n.elems[i], fieldByIndex(val, f.index), kp); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// aliasKeys records the JSON keys of an object that are decoded to each
+// member of a struct, by the member's index, to find a member with an alias
+// key and another key.
+type aliasKeys []struct {
+	key   string
+	pos   Position
+	alias bool
+	set   bool
+}
+
+// add records the JSON key 'k' at 'pos' for the member 'j'.  If there's
+// already a key for the member and either is an alias it returns the earlier
+// key and false.
+func (a aliasKeys) add(j int, k string, pos Position, alias bool) (string, bool) {
+	if a[j].set {
+		if a[j].alias || alias {
+			return a[j].key, false
+		}
+		return "", true
+	}
+	a[j].key, a[j].pos, a[j].alias, a[j].set = k, pos, alias, true
+	return "", true
+}
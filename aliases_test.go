@@ -0,0 +1,93 @@
+package checkjson
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type alServer struct {
+	Host    string `json:"host" checkjson:"alias=hostname"`
+	Timeout int    `json:"timeout" checkjson:"alias=timeout_ms|timeoutMs"`
+}
+
+type alConfig struct {
+	Name    string     `json:"name" checkjson:"alias=title"`
+	Servers []alServer `json:"servers"`
+	Primary *alServer  `json:"primary"`
+}
+
+func TestAliases(t *testing.T) {
+	fmt.Println("===================== TestAliases ...")
+
+	data := []byte(`{"title":"a","servers":[{"hostname":"h1","timeout_ms":5},{"host":"h2","TimeoutMs":6}],"primary":{"HOSTNAME":"p"}}`)
+	if err := Validate(data, new(alConfig)); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := UnknownJSONKeys(data, new(alConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 0 {
+		t.Fatal("unknown:", keys)
+	}
+	mems, err := MissingJSONKeys(data, new(alConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(mems); s != "[primary.timeout]" {
+		t.Fatal("missing:", s)
+	}
+
+	var c alConfig
+	if err := Unmarshal(data, &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Name != "a" || len(c.Servers) != 2 || c.Servers[0].Host != "h1" || c.Servers[0].Timeout != 5 ||
+		c.Servers[1].Host != "h2" || c.Servers[1].Timeout != 6 || c.Primary == nil || c.Primary.Host != "p" {
+		t.Fatalf("decoded: %+v", c)
+	}
+
+	b, err := RewriteAliases([]byte(`{"title":"a","x":1.50,"primary":{"timeoutMs":12345678901234567890}}`), new(alConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(b); s != `{"name":"a","x":1.50,"primary":{"timeout":12345678901234567890}}` {
+		t.Fatal("rewritten:", s)
+	}
+
+	// strict case matching applies to aliases as it does to JSON names
+	err = NewChecker(StrictCase(true)).Validate([]byte(`{"Title":"a"}`), new(alConfig))
+	var cerr *CaseMismatchError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("not a CaseMismatchError: %v", err)
+	}
+}
+
+func TestAliasConflict(t *testing.T) {
+	fmt.Println("===================== TestAliasConflict ...")
+
+	data := []byte(`{"servers":[{"timeout":5,"timeout_ms":6}]}`)
+	err := Validate(data, new(alConfig))
+	var ae *AliasError
+	if !errors.As(err, &ae) {
+		t.Fatalf("not an AliasError: %v", err)
+	}
+	if ae.Other != "timeout" || ae.Path.String() != "servers.1.timeout_ms" {
+		t.Fatalf("AliasError: %s %s", ae.Other, ae.Path)
+	}
+	if s := ae.Error(); s != "JSON keys: timeout and timeout_ms - are for the same member: Timeout" {
+		t.Fatal(s)
+	}
+	if _, err := RewriteAliases(data, new(alConfig)); err == nil {
+		t.Fatal("no error for RewriteAliases")
+	}
+	if err := Unmarshal([]byte(`{"primary":{"timeout_ms":1,"timeoutMs":2}}`), new(alConfig)); err == nil {
+		t.Fatal("no error for Unmarshal")
+	}
+
+	// two keys for a member that differ in case aren't an alias conflict
+	if err := Validate([]byte(`{"name":"a","NAME":"b"}`), new(alConfig)); err != nil {
+		t.Fatal(err)
+	}
+}
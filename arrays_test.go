@@ -0,0 +1,92 @@
+package checkjson
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type arrPoint struct {
+	X, Y int
+}
+
+type arrCell struct {
+	Value string
+}
+
+type arrPtr *arrPtr
+
+type arrShapes struct {
+	Triangle [3]arrPoint
+	Grid     [][]arrCell
+	Path     *[]arrPoint
+	Origin   **arrPoint
+	Corners  [2]*arrPoint
+	Data     []byte
+	Loop     arrPtr
+}
+
+func TestArrays(t *testing.T) {
+	fmt.Println("===================== TestArrays ...")
+
+	data := []byte(`{
+		"triangle":[{"x":0,"y":0},{"x":1,"y":0},{"x":0,"z":1}],
+		"grid":[[{"value":"a"}],[{"value":"b"},{"valeu":"c"}]],
+		"path":[{"x":1},{"w":2}],
+		"origin":{"x":0,"y":0,"z":0},
+		"corners":[{"x":1},{"y":2,"v":3}],
+		"data":"aGVsbG8=",
+		"loop":{"a":1}
+	}`)
+	keys, err := UnknownJSONKeys(data, new(arrShapes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "[triangle.3.z grid.2.2.valeu path.2.w origin.z corners.2.v]"
+	if s := fmt.Sprint(keys); s != want {
+		t.Fatal("unknown:", s, "!=", want)
+	}
+
+	data = []byte(`{"data":[104,105],"grid":[[{"value":"a"}]]}`)
+	if err := decodeStrict(data, new(arrShapes)); err != nil {
+		t.Fatal("encoding/json:", err)
+	}
+	if err := Validate(data, new(arrShapes)); err != nil {
+		t.Fatal(err)
+	}
+
+	data = []byte(`{"grid":[{"value":"a"}]}`)
+	err = Validate(data, new(arrShapes))
+	var aerr *NotArrayError
+	if !errors.As(err, &aerr) || aerr.Path.String() != "grid.1" {
+		t.Fatalf("err: %v", err)
+	}
+	fmt.Println("err ok:", err)
+}
+
+func TestArrayLength(t *testing.T) {
+	fmt.Println("===================== TestArrayLength ...")
+
+	data := []byte(`{"triangle":[{"x":0},{"x":1},{"x":2},{"x":3},{"x":4}],"corners":[{"x":0}]}`)
+	// encoding/json silently drops the extra elements
+	if err := decodeStrict(data, new(arrShapes)); err != nil {
+		t.Fatal("encoding/json:", err)
+	}
+	err := Validate(data, new(arrShapes))
+	var lerr *ArrayLengthError
+	if !errors.As(err, &lerr) {
+		t.Fatalf("not an ArrayLengthError: %v", err)
+	}
+	if lerr.Path.String() != "triangle" || lerr.Len != 5 || lerr.Type.Len() != 3 {
+		t.Fatalf("ArrayLengthError: %#v", lerr)
+	}
+	fmt.Println("err ok:", err)
+
+	keys, err := UnknownJSONKeys(data, new(arrShapes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(keys); s != "[triangle.4 triangle.5]" {
+		t.Fatal("unknown:", s)
+	}
+}
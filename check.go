@@ -0,0 +1,708 @@
+// check.go - check JSON object against struct definition in a single pass
+// Copyright © 2016-2019 Charles Banning.  All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package checkjson
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A Report is the result of Check.  The lists are in a stable order: JSON
+// keys are listed in the order they occur in the JSON object and struct
+// members in the order they are defined in the struct.
+type Report struct {
+	// Unknown are the JSON keys that will not be decoded - see UnknownJSONKeys.
+	Unknown []string
+	// Suggestions are the JSON keys that the Unknown keys may have been meant
+	// for, by the Unknown key: the JSON names of sibling members with a
+	// similar spelling, best first; e.g., "timeout" for "timout".  Unknown
+	// keys without a similar member name are not listed.
+	Suggestions map[string][]string
+	// Missing are the struct members that will not be set - see MissingJSONKeys.
+	Missing []string
+	// Existing are the struct members that will be set - see ExistingJSONKeys.
+	Existing []string
+	// Mismatched are the JSON keys that will be decoded but are not spelled
+	// the same as the member's JSON tag; e.g., "whyNot" for `json:"whynot"`.
+	// The keys are listed in dot-notation, with the last key as it is in the
+	// JSON object.
+	Mismatched []string
+	// CaseMismatched are the JSON keys that match a member's JSON name only
+	// if case is ignored, with the member's spelling.  They are only listed
+	// in strict-case mode - see StrictCase - when they are not decoded.
+	CaseMismatched []CaseMismatch
+	// Warnings are findings that don't stop the JSON object from being
+	// decoded, but that may not be intended; e.g., a *FieldConflict for
+	// each struct type that is checked with members that conflict.
+	Warnings []error
+	// Findings are the entries of the other lists, the Warnings and the
+	// ValidateAll errors, with their positions in the JSON object.  The JSON
+	// keys, errors and warnings are in the order they are found, followed
+	// by the struct members.
+	Findings []Finding
+}
+
+// A CaseMismatch is a JSON key that matches a struct member's JSON name only
+// if case is ignored.
+type CaseMismatch struct {
+	Key  string // dot-notation path, with the last key as it is in the JSON object
+	Name string // the member's JSON tag or field name
+}
+
+// Check scans a JSON object and reports the results of UnknownJSONKeys,
+// MissingJSONKeys and ExistingJSONKeys for the struct 'val', as well as the
+// JSON keys whose case does not match a JSON tag.  The JSON object is decoded
+// and compared with 'val' just once, so it is more efficient than calling
+// each of the functions.
+func Check(b []byte, val interface{}) (*Report, error) {
+	return std.Check(b, val)
+}
+
+// Check is like the package level Check function but uses the Checker's
+// settings.
+func (c *Checker) Check(b []byte, val interface{}) (*Report, error) {
+	w, err := c.walk(b, val)
+	if err != nil {
+		return nil, err
+	}
+	return w.r, nil
+}
+
+// walk decodes the JSON object 'b' and compares it with 'val', collecting
+// the Report and the Validate errors.
+func (c *Checker) walk(b []byte, val interface{}) (*walker, error) {
+	w := c.newWalker(bytes.NewReader(b))
+	if err := w.run(val); err != nil {
+		return nil, ResolveJSONError(b, err)
+	}
+	return w, nil
+}
+
+// newWalker returns a walker that collects the Report for the JSON object
+// read from 'r'.
+func (c *Checker) newWalker(r io.Reader) *walker {
+	return &walker{
+		c: c,
+		s: newScanner(r),
+		r: &Report{
+			Unknown:        make([]string, 0),
+			Suggestions:    make(map[string][]string),
+			Missing:        make([]string, 0),
+			Existing:       make([]string, 0),
+			Mismatched:     make([]string, 0),
+			CaseMismatched: make([]CaseMismatch, 0),
+			Warnings:       make([]error, 0),
+			Findings:       make([]Finding, 0),
+		},
+		seen: make(map[reflect.Type]bool),
+	}
+}
+
+// run reads the JSON object and compares it with 'val'.  It returns the
+// error that json.Unmarshal reports for 'b' if the JSON object can't be
+// read; see scanner.
+func (w *walker) run(val interface{}) error {
+	m := &members{}
+	n := w.s.top()
+	if n.kind == nullKind {
+		n.kind = objectKind // as json.Unmarshal does, decode nothing
+	}
+	w.walk(n, reflect.ValueOf(val), nil, m)
+	w.s.end()
+	if w.s.err != nil && w.s.err != errStopped {
+		return w.s.err
+	}
+	if w.r != nil {
+		for _, f := range m.findings {
+			if f.Kind == MissingMember {
+				w.r.Missing = append(w.r.Missing, f.Path)
+			} else {
+				w.r.Existing = append(w.r.Existing, f.Path)
+			}
+		}
+		w.r.Findings = append(w.r.Findings, m.findings...)
+	}
+	w.reqs = m.required
+	return nil
+}
+
+// skipKey reports whether the JSON key at 'p' is not to be validated.
+func (c *Checker) skipKey(p Path) bool {
+	for _, sk := range c.skipkeys {
+		if sk.match(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// skipMember reports whether the struct member at 'p' is not to be looked
+// for in the JSON object.
+func (c *Checker) skipMember(p Path) bool {
+	for _, sm := range c.skipmembers {
+		if sm.match(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// format returns 'p' in the Checker's path format; 'dot' is the dot-notation
+// of the list that the path is for.
+func (c *Checker) format(p Path, dot func(Path) string) string {
+	return p.format(c.pathstyle, dot)
+}
+
+// deprecatedKey returns the replacement hint if the member 'f' for the JSON
+// key at 'p' is deprecated.
+func (c *Checker) deprecatedKey(p Path, f *field) (string, bool) {
+	if f.deprecated {
+		return f.hint, true
+	}
+	if len(c.deprecated) == 0 {
+		return "", false
+	}
+	hint, ok := c.deprecated[strings.ToLower(p.members())]
+	return hint, ok
+}
+
+// members collects the struct member paths found by a walk, so they can be
+// listed in struct definition order whatever the order of the JSON keys.
+type members struct {
+	findings []Finding // missing and existing members
+	paths    []Path    // the path of each finding
+	required []error   // missing required members, for RequireKeys
+	quiet    bool      // the members are of an ignored member, so aren't listed
+}
+
+func (m *members) add(sub *members) {
+	m.findings = append(m.findings, sub.findings...)
+	m.paths = append(m.paths, sub.paths...)
+	m.required = append(m.required, sub.required...)
+}
+
+// merge adds the members of the value of a repeated JSON key, 'dup', to
+// those of its earlier value.  As encoding/json decodes both values to the
+// member, a member is existing if either value has it and missing if
+// neither has.  The members are listed in struct definition order.
+func (m *members) merge(dup *members) {
+	exist := make(map[string]bool)
+	for _, v := range []*members{m, dup} {
+		for i, f := range v.findings {
+			if f.Kind == ExistingMember {
+				exist[v.paths[i].members()] = true
+			}
+		}
+	}
+	type finding struct {
+		f Finding
+		p Path
+	}
+	listed := make(map[string]bool) // by kind and member
+	var merged []finding
+	for _, v := range []*members{m, dup} {
+		for i, f := range v.findings {
+			id := v.paths[i].members()
+			if f.Kind == MissingMember && exist[id] || v == dup && listed[f.Kind.String()+":"+id] {
+				continue
+			}
+			listed[f.Kind.String()+":"+id] = true
+			merged = append(merged, finding{f, v.paths[i]})
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return lessPath(merged[i].p, merged[j].p)
+	})
+	m.findings, m.paths = m.findings[:0], m.paths[:0]
+	for _, f := range merged {
+		m.findings = append(m.findings, f.f)
+		m.paths = append(m.paths, f.p)
+	}
+	var required []error
+	for _, err := range append(m.required, dup.required...) {
+		id := err.(*MissingKeyError).Path.members()
+		if !exist[id] && !listed["required:"+id] {
+			listed["required:"+id] = true
+			required = append(required, err)
+		}
+	}
+	m.required = required
+}
+
+// lessPath reports whether the member at 'x' comes before the one at 'y'
+// in struct definition order.  Array elements are in index order; map
+// entries are left in the order they were found.
+func lessPath(x, y Path) bool {
+	for i := 0; i < len(x) && i < len(y); i++ {
+		a, b := x[i], y[i]
+		switch {
+		case a.field != nil && b.field != nil:
+			if lessIndex(a.field.index, b.field.index) {
+				return true
+			}
+			if lessIndex(b.field.index, a.field.index) {
+				return false
+			}
+		case a.Index >= 0 && b.Index >= 0 && a.Index != b.Index:
+			return a.Index < b.Index
+		}
+	}
+	return len(x) < len(y)
+}
+
+// walker compares a JSON value with a struct value as it is read.  JSON
+// keys and errors are reported as they are found; struct members are
+// collected in 'm' by walk.  The results are collected in a Report or, if
+// 'yield' is set, passed to 'yield' as they are found - see Findings.
+type walker struct {
+	c     *Checker
+	s     *scanner
+	r     *Report               // nil if 'yield' is set
+	yield func(Finding) bool    // returns false to stop the walk
+	errs  []error               // for Validate, in the order found
+	reqs  []error               // for RequireKeys, in struct definition order
+	seen  map[reflect.Type]bool // struct types with conflicts reported
+}
+
+// walk compares the JSON value 'n' with 'val'.  It reads the whole of the
+// value, whatever is found.
+func (w *walker) walk(n *node, val reflect.Value, p Path, m *members) {
+	defer w.s.skip(n)
+
+	// 0. As with encoding/json, null is decoded to anything; but it only
+	//    changes a pointer, slice, map or interface - to nil - or a map entry.
+	if n.kind == nullKind {
+		w.checkNull(val, p)
+		return
+	}
+
+	// 1. Convert any pointer value, following pointer chains - **T.
+	//    An interface value is checked as its dynamic value if that is a
+	//    pointer, as encoding/json decodes into it, or else as the concrete
+	//    type registered for the interface type.
+	//    A value that decodes itself - e.g., json.RawMessage or time.Time -
+	//    is not checked, unless it describes its JSON value with JSONShape.
+	shaped := false
+	for {
+		// zero Value?
+		if !val.IsValid() {
+			return
+		}
+		if !shaped {
+			if s, ok := shape(val); ok {
+				val, shaped = s, true
+				continue
+			}
+		}
+		if unmarshaler(val.Type()) {
+			return
+		}
+		if val.Kind() == reflect.Interface {
+			if val.IsNil() || val.Elem().Kind() != reflect.Ptr || val.Elem().IsNil() {
+				t := val.Type()
+				var ok bool
+				if val, ok = interfaceValue(t); !ok {
+					if t.NumMethod() == 0 {
+						w.checkFloat64(n, t, p)
+					}
+					return // no way to know what will be decoded
+				}
+				continue
+			}
+			val = val.Elem()
+			continue
+		}
+		if val.Kind() != reflect.Ptr {
+			break
+		}
+		if val.Type().Elem() == val.Type() {
+			return // type P *P
+		}
+		if val.IsNil() {
+			val = reflect.New(val.Type().Elem())
+		}
+		val = val.Elem()
+	}
+	typ := val.Type()
+
+	// 2. If its a slice or array then 'n' should hold a JSON array.
+	//    Loop through the members of 'n' and see that they are valid relative
+	//    to the <T> of val []<T> or val [N]<T>.
+	if typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array {
+		if n.kind != arrayKind {
+			// []byte is decoded from a base64 encoded JSON string
+			if typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8 && n.kind == stringKind {
+				return
+			}
+			// encoding/json must have a JSON array value to decode
+			w.mismatch(p, &NotArrayError{p, typ})
+			return
+		}
+		// slice may be nil or short, so create a Value of it's type;
+		// existing elements are decoded into, as with encoding/json
+		sval := reflect.New(typ.Elem()).Elem()
+		i := 0
+		for ; w.s.more(n); i++ {
+			e := w.s.value()
+			ep := p.elem(i).at(e.pos)
+			switch {
+			case w.c.skipKey(ep):
+				w.s.skip(e)
+			case typ.Kind() == reflect.Array && i >= typ.Len():
+				// encoding/json drops the extra elements
+				w.unknown(ep, nil)
+				w.s.skip(e)
+			case i < val.Len():
+				w.walk(e, val.Index(i), ep, m)
+			default:
+				w.walk(e, sval, ep, m)
+			}
+		}
+		if typ.Kind() == reflect.Array && i > typ.Len() {
+			w.fail(p, p.Position(), &ArrayLengthError{p, typ, i})
+		}
+		return
+	}
+
+	// 3. If its a map then 'n' should hold a JSON object.
+	//    Check that the keys decode to the map key type and that the values
+	//    are valid relative to the <T> of val map[K]<T>.
+	if typ.Kind() == reflect.Map {
+		if n.kind != objectKind {
+			w.mismatch(p, &NotObjectError{p, typ})
+			return
+		}
+		w.walkMap(n, val, p, m)
+		return
+	}
+
+	// 4a. Anything that's not a struct must be decodable from the JSON value.
+	if typ.Kind() != reflect.Struct {
+		if !decodable(n, typ) {
+			w.fail(p, p.Position(), &TypeMismatchError{p, typ, n.kind.String(), n.value()})
+		} else if n.kind == numberKind {
+			w.checkNumber(n, typ, p)
+		}
+		return // don't look for k:v pairs
+	}
+	// 4b. 'n' must represent k:v pairs
+	if n.kind != objectKind {
+		w.mismatch(p, &NotObjectError{p, typ})
+		return
+	}
+	w.walkObject(n, val, p, m)
+}
+
+// mismatch records a JSON value that can't be decoded to the member at 'p'.
+// The member has a JSON key, so it is listed as existing, not missing.
+func (w *walker) mismatch(p Path, err error) {
+	w.unknown(p, nil)
+	w.fail(p, p.Position(), err)
+}
+
+func (w *walker) walkMap(n *node, val reflect.Value, p Path, m *members) {
+	typ := val.Type()
+	// map may be nil, so create a Value of it's element type
+	mval := reflect.New(typ.Elem()).Elem()
+	for w.s.more(n) {
+		k, pos := w.s.key()
+		kp := p.entry(k).at(pos)
+		e := w.s.value()
+		if w.c.skipKey(kp) {
+			w.s.skip(e)
+			continue
+		}
+		if err := checkMapKey(k, typ.Key()); err != nil {
+			w.unknown(kp, nil)
+			w.fail(p, kp.Position(), &MapKeyError{kp, typ, err})
+			w.s.skip(e)
+			continue
+		}
+		w.walk(e, mval, kp, m)
+	}
+}
+
+// checkNull warns of a JSON null value at 'p' for 'val' that won't change it.
+func (w *walker) checkNull(val reflect.Value, p Path) {
+	if !val.IsValid() || len(p) == 0 || p[len(p)-1].entry {
+		return
+	}
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		return
+	}
+	if reflect.PointerTo(val.Type()).Implements(jsonUnmarshalerType) {
+		return // null is passed to UnmarshalJSON, which may change the value
+	}
+	w.warn(p.Position(), &NullWarning{p, val.Type(), w.c.pathstyle})
+}
+
+// walkQuoted checks the JSON value for a member with the ",string" tag
+// option: as with encoding/json it must be null or a JSON string holding a
+// value that can be decoded to the member's type 't'.
+func (w *walker) walkQuoted(n *node, t reflect.Type, p Path) {
+	defer w.s.skip(n)
+	if n.kind == nullKind {
+		w.checkNull(reflect.New(t).Elem(), p)
+		return
+	}
+	if unmarshaler(t) {
+		return
+	}
+	if n.kind != stringKind {
+		w.fail(p, p.Position(), &StringOptionError{p, t, n.kind.String(), n.value(), nil})
+		return
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if err := checkQuoted(n.text, t); err != nil {
+		w.fail(p, p.Position(), &StringOptionError{p, t, n.kind.String(), n.value(), err})
+	}
+}
+
+// checkQuoted returns an error if 's', the content of a JSON string, can't
+// be decoded to a member of type 't' with the ",string" tag option.  As with
+// encoding/json 's' must be null or a JSON literal of the kind of 't' without
+// surrounding white space, except that numbers are parsed with strconv -
+// so "01" and "+1" are accepted.
+func checkQuoted(s string, t reflect.Type) error {
+	if s == "null" {
+		return nil
+	}
+	if len(s) == 0 {
+		return errors.New("empty value")
+	}
+	switch c := s[0]; {
+	case c == 't' || c == 'f':
+		if t.Kind() != reflect.Bool || (s != "true" && s != "false") {
+			return fmt.Errorf("not a %s value: %s", t, s)
+		}
+	case c == '"':
+		var v string
+		if t.Kind() != reflect.String || s[len(s)-1] != '"' {
+			return fmt.Errorf("not a %s value: %s", t, s)
+		}
+		return json.Unmarshal([]byte(s), &v)
+	case c == '-' || c == '+' || ('0' <= c && c <= '9'):
+		var err error
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			_, err = strconv.ParseInt(s, 10, t.Bits())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			_, err = strconv.ParseUint(s, 10, t.Bits())
+		case reflect.Float32, reflect.Float64:
+			_, err = strconv.ParseFloat(s, t.Bits())
+		default:
+			err = fmt.Errorf("not a %s value: %s", t, s)
+		}
+		return err
+	default:
+		return fmt.Errorf("not a JSON literal: %s", s)
+	}
+	return nil
+}
+
+// decodable reports whether encoding/json can decode the JSON value 'n' to
+// a value of type 't', which isn't a slice, array, map or struct.
+func decodable(n *node, t reflect.Type) bool {
+	switch n.kind {
+	case boolKind:
+		return t.Kind() == reflect.Bool
+	case numberKind:
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+			reflect.Float32, reflect.Float64:
+			return true
+		case reflect.String:
+			return t == numberType
+		}
+	case stringKind:
+		if t == numberType {
+			return isNumber(n.text) // as with encoding/json
+		}
+		return t.Kind() == reflect.String
+	case nullKind:
+		return true
+	}
+	return false
+}
+
+var numberType = reflect.TypeOf(json.Number(""))
+
+// checkMapKey returns an error if the JSON key 'k' can't be decoded to a
+// map key of type 'kt'.  As with encoding/json the key type must implement
+// encoding.TextUnmarshaler, or be a string, integer or floating-point type.
+func checkMapKey(k string, kt reflect.Type) error {
+	if reflect.PointerTo(kt).Implements(textUnmarshalerType) {
+		return reflect.New(kt).Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(k))
+	}
+	var err error
+	switch kt.Kind() {
+	case reflect.String:
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		_, err = strconv.ParseInt(k, 10, kt.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		_, err = strconv.ParseUint(k, 10, kt.Bits())
+	case reflect.Float32, reflect.Float64:
+		_, err = strconv.ParseFloat(k, kt.Bits())
+	default:
+		err = fmt.Errorf("unsupported map key type: %s", kt)
+	}
+	return err
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+func (w *walker) walkObject(n *node, val reflect.Value, p Path, m *members) {
+	// 5. Get the struct fields and their key:index maps.
+	sf := cachedTypeFields(val.Type())
+	fields := sf.list
+	if !w.seen[val.Type()] {
+		w.seen[val.Type()] = true
+		for _, c := range sf.conflicts {
+			w.warn(n.pos, c)
+		}
+	}
+
+	// 6. Check that JSON keys correspond to exported field names, in
+	//    document order, and collect the members of nested objects.
+	found := make([]*members, len(fields))
+	keys := make(aliasKeys, len(fields))
+	for w.s.more(n) {
+		k, pos := w.s.key()
+		kp := p.child(k, nil).at(pos)
+		e := w.s.value()
+		j, name, exact, ok := sf.lookup(k)
+		if w.c.skipKey(kp) {
+			// the key isn't validated, but encoding/json still sets the
+			// member, so it isn't missing
+			if ok && (exact || !w.c.strictcase) && nilEmbedded(val, fields[j].index) == nil {
+				keys.add(j, k, kp.Position(), name != fields[j].jsonName())
+				if found[j] == nil && (e.kind != nullKind || !w.c.nullsmissing) {
+					found[j] = &members{}
+				}
+			}
+			w.s.skip(e)
+			continue
+		}
+		if ok && !exact {
+			if w.c.strictcase {
+				// a case-sensitive decoder won't set the member
+				kp[len(kp)-1].field = &fields[j]
+				w.mismatched(kp, name, true)
+				w.fail(p, kp.Position(), &CaseMismatchError{kp, val.Type(), name})
+				w.s.skip(e)
+				continue
+			}
+		}
+		if !ok {
+			sugg := sf.suggest(k, func(f *field) bool {
+				return w.c.skipMember(p.child(f.label(), f))
+			})
+			w.unknown(kp, sugg)
+			w.fail(p, kp.Position(), &UnknownKeyError{kp, val.Type(), sugg})
+			w.s.skip(e)
+			continue
+		}
+		f := &fields[j]
+		kp[len(kp)-1].field = f
+		repeated := keys[j].set
+		if other, ok := keys.add(j, k, kp.Position(), name != f.jsonName()); !ok {
+			w.fail(p, kp.Position(), &AliasError{kp, val.Type(), other})
+			w.s.skip(e)
+			continue
+		}
+		if repeated {
+			w.warn(kp.Position(), &DuplicateKeyWarning{kp, keys[j].key, w.c.pathstyle})
+		}
+		if et := nilEmbedded(val, f.index); et != nil && !f.ignored {
+			// encoding/json can't set the embedded pointer
+			w.unknown(kp, nil)
+			w.fail(p, kp.Position(), &EmbeddedPointerError{kp, et})
+			w.s.skip(e)
+			continue
+		}
+		if len(f.rawtag) > 0 && f.rawtag != k && name == f.rawtag { // JSON key case doesn't match Field tag
+			w.mismatched(kp, name, false)
+		}
+		if hint, ok := w.c.deprecatedKey(kp, f); ok {
+			w.warn(kp.Position(), &DeprecatedKeyWarning{kp, hint, w.c.pathstyle})
+		}
+		fm := found[j]
+		if fm == nil {
+			// the members of an ignored member aren't listed
+			fm = &members{quiet: m.quiet || w.c.skipMember(kp)}
+			// null is counted as if the key were absent, if so configured
+			if e.kind != nullKind || !w.c.nullsmissing {
+				found[j] = fm
+			}
+		} else {
+			// the value of a repeated key; its members are merged with
+			// those found so far, which have already been passed to
+			// 'yield' if it's set, so then they aren't listed
+			fm = &members{quiet: fm.quiet || w.r == nil}
+		}
+		switch {
+		case f.ignored:
+			w.s.skip(e) // don't drill down further
+		case f.norecurse:
+			// the value is checked, but its members aren't listed
+			w.walk(e, fieldByIndex(val, f.index), kp, &members{quiet: true})
+		case f.quoted:
+			w.walkQuoted(e, f.typ, kp)
+		default:
+			w.walk(e, fieldByIndex(val, f.index), kp, fm)
+		}
+		if found[j] != nil && found[j] != fm {
+			found[j].merge(fm)
+		}
+	}
+
+	// 7. Check that field names/tags have a corresponding JSON key, in
+	//    struct definition order.
+	for j := range fields {
+		f := &fields[j]
+		if f.ignored {
+			continue
+		}
+		// the JSON key for the member, if any, else its JSON name
+		fp := p.child(f.jsonName(), f).at(n.pos)
+		if keys[j].set {
+			fp = p.child(keys[j].key, f).at(keys[j].pos)
+		}
+		if w.c.skipMember(fp) {
+			continue
+		}
+		if found[j] == nil {
+			if f.required && w.r != nil {
+				m.required = append(m.required, &MissingKeyError{fp, val.Type(), w.c.pathstyle})
+			}
+			if _, ok := w.c.deprecatedKey(fp, f); ok || f.optional {
+				continue // neither missing nor existing
+			}
+			// If JSON key is missing, then record it if it's required,
+			// or there's no omitempty tag or we're ignoring omitempty tag.
+			if f.required || !f.omitempty || !w.c.omitemptyOK {
+				w.member(m, MissingMember, fp)
+				continue
+			}
+		}
+		w.member(m, ExistingMember, fp)
+		if found[j] != nil {
+			m.add(found[j])
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package checkjson
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	fmt.Println("===================== TestCheck ...")
+
+	type test3 struct {
+		Something string
+		Else      string
+	}
+	type test2 struct {
+		Why     string
+		Not     string `json:"not"`
+		Another test3
+	}
+	type test struct {
+		Ok   bool
+		Why  string
+		More []test2
+	}
+
+	tv := test{}
+	data := []byte(`{
+		"zed":1,
+		"ok":true,
+		"more":[
+			{"why":"again","another":{"else":"ok","other":0},"whynot":false},
+			{"Not":"this","another":{"something":"a thing","else":"ok"}}
+		],
+		"abc":2
+	}`)
+
+	r, err := Check(data, tv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "[zed more.1.another.other more.1.whynot abc]"
+	if s := fmt.Sprint(r.Unknown); s != want {
+		t.Fatal("unknown:", s, "!=", want)
+	}
+	want = "[Why More.not More.Another.Something More.Why]"
+	if s := fmt.Sprint(r.Missing); s != want {
+		t.Fatal("missing:", s, "!=", want)
+	}
+	want = "[Ok More More.Why More.Another More.Another.Else More.Not More.Another More.Another.Something More.Another.Else]"
+	if s := fmt.Sprint(r.Existing); s != want {
+		t.Fatal("existing:", s, "!=", want)
+	}
+	want = "[more.2.Not]"
+	if s := fmt.Sprint(r.Mismatched); s != want {
+		t.Fatal("mismatched:", s, "!=", want)
+	}
+
+	// the lists are the same as those from the individual functions
+	unknown, _ := UnknownJSONKeys(data, tv)
+	missing, _ := MissingJSONKeys(data, tv)
+	existing, _ := ExistingJSONKeys(data, tv)
+	if fmt.Sprint(unknown) != fmt.Sprint(r.Unknown) ||
+		fmt.Sprint(missing) != fmt.Sprint(r.Missing) ||
+		fmt.Sprint(existing) != fmt.Sprint(r.Existing) {
+		t.Fatal("Check and the individual functions differ")
+	}
+
+	// and the order is stable
+	for i := 0; i < 20; i++ {
+		rr, _ := Check(data, tv)
+		if fmt.Sprint(rr) != fmt.Sprint(r) {
+			t.Fatal("report changed:", rr, "!=", r)
+		}
+	}
+}
+
+func TestCheckIgnoredKey(t *testing.T) {
+	fmt.Println("===================== TestCheckIgnoredKey ...")
+
+	// an ignored key isn't validated, but encoding/json still sets its member
+	type test struct {
+		Config string
+		Name   string
+	}
+	data := []byte(`{"config":"x","name":"y"}`)
+	r, err := NewChecker().Check(data, test{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(r.Unknown, r.Missing, r.Existing); s != "[] [] [Config Name]" {
+		t.Fatal("unknown, missing, existing:", s)
+	}
+
+	// at any depth
+	type sub struct {
+		Comment string
+		X       int
+	}
+	type test2 struct {
+		Comment string
+		Sub     sub
+	}
+	data = []byte(`{"comment":"a","sub":{"comment":"b","x":1,"y":2}}`)
+	r, err = NewChecker(KeysToIgnore("comment")).Check(data, test2{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(r.Unknown, r.Missing, r.Existing); s != "[sub.y] [] [Comment Sub Sub.Comment Sub.X]" {
+		t.Fatal("unknown, missing, existing:", s)
+	}
+}
+
+func TestCheckNoRecurse(t *testing.T) {
+	fmt.Println("===================== TestCheckNoRecurse ...")
+
+	// the members of a norecurse member aren't listed, but its value is checked
+	type sub struct {
+		X int
+		Y int
+	}
+	type test struct {
+		Name string
+		Sub  sub `checkjson:"norecurse"`
+	}
+	data := []byte(`{"name":"a","sub":{"x":1,"z":2}}`)
+	r, err := Check(data, new(test))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(r.Unknown, r.Missing, r.Existing); s != "[sub.z] [] [Name Sub]" {
+		t.Fatal("unknown, missing, existing:", s)
+	}
+	var uerr *UnknownKeyError
+	if err := Validate(data, new(test)); !errors.As(err, &uerr) || uerr.Path.String() != "sub.z" {
+		t.Fatalf("not an UnknownKeyError for sub.z: %v", err)
+	}
+
+	data = []byte(`{"name":"a","sub":{"x":"1"}}`)
+	var terr *TypeMismatchError
+	if err := ValidateAll(data, new(test)); !errors.As(err, &terr) || terr.Path.String() != "sub.x" {
+		t.Fatalf("not a TypeMismatchError for sub.x: %v", err)
+	}
+	keys, err := UnknownJSONKeys([]byte(`{"sub":[]}`), new(test))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(keys); s != "[sub]" {
+		t.Fatal("unknown:", s)
+	}
+}
+
+func TestCheckError(t *testing.T) {
+	fmt.Println("===================== TestCheckError ...")
+
+	type test struct {
+		Ok bool
+	}
+	tv := test{}
+	for _, data := range []string{``, `[{"ok":true}]`, `{"ok":true}}`, `{"ok":tru}`} {
+		r, err := Check([]byte(data), tv)
+		if err == nil {
+			t.Fatal("no error for:", data)
+		}
+		if r != nil {
+			t.Fatal("report for:", data)
+		}
+		fmt.Println("err ok:", err)
+	}
+}
+
+func TestCheckDuplicateKeys(t *testing.T) {
+	fmt.Println("===================== TestCheckDuplicateKeys ...")
+
+	type server struct {
+		Port int
+		Host string `checkjson:"required"`
+	}
+	type test struct {
+		Srv  server `json:"srv"`
+		Name string
+	}
+	// encoding/json decodes both values of "srv" to the member, merging them
+	data := []byte(`{"srv":{"port":1},"srv":{"host":"a"},"Name":"x","name":"y"}`)
+	r, err := Check(data, new(test))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Println("report:", r)
+	if s := fmt.Sprint(r.Missing, r.Existing); s != "[] [Srv srv.Port srv.Host Name]" {
+		t.Fatal("missing, existing:", s)
+	}
+	if len(r.Warnings) != 2 {
+		t.Fatal("warnings:", r.Warnings)
+	}
+	var derr *DuplicateKeyWarning
+	if !errors.As(r.Warnings[0], &derr) || derr.Path.String() != "srv" || derr.Other != "srv" {
+		t.Fatalf("not a DuplicateKeyWarning for srv: %v", r.Warnings[0])
+	}
+	if s := r.Warnings[1].Error(); s != "duplicate JSON key: name - the member already has JSON key: Name" {
+		t.Fatal("warning:", s)
+	}
+	if err := RequireKeys(data, new(test)); err != nil {
+		t.Fatal(err)
+	}
+
+	// missing from both values
+	data = []byte(`{"srv":{"port":1},"srv":{"port":2},"name":"x"}`)
+	r, err = Check(data, new(test))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(r.Missing, r.Existing); s != "[srv.Host] [Srv srv.Port Name]" {
+		t.Fatal("missing, existing:", s)
+	}
+	if err := RequireKeys(data, new(test)); err == nil || err.Error() != "missing required JSON key: srv.Host" {
+		t.Fatal("RequireKeys:", err)
+	}
+
+	// the merged members are in struct definition order, not key order
+	data = []byte(`{"srv":{"host":"a"},"srv":{"port":1},"name":"x"}`)
+	r, err = Check(data, new(test))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(r.Missing, r.Existing); s != "[] [Srv srv.Port srv.Host Name]" {
+		t.Fatal("missing, existing:", s)
+	}
+	type nested struct {
+		A   int
+		Srv server `json:"srv"`
+		Z   int
+	}
+	type outer struct {
+		N nested `json:"n"`
+	}
+	data = []byte(`{"n":{"z":1},"n":{"srv":{"host":"a"}},"n":{"a":1,"srv":{"port":2}}}`)
+	r, err = Check(data, new(outer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(r.Missing, r.Existing); s != "[] [N n.A n.Srv n.srv.Port n.srv.Host n.Z]" {
+		t.Fatal("missing, existing:", s)
+	}
+}
@@ -0,0 +1,159 @@
+// checker.go - per-instance settings for checking JSON objects
+// Copyright © 2016-2019 Charles Banning.  All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package checkjson
+
+import (
+	"strings"
+)
+
+// A Checker checks JSON objects against struct definitions using its own
+// list of keys and members to ignore, its own "omitempty" and null handling
+// and its own case matching.
+// The settings are fixed when the Checker is created with NewChecker, so
+// a single Checker can be used by multiple goroutines, and Checkers with
+// different settings can be used concurrently.
+//
+// The package level functions - Validate, UnknownJSONKeys, MissingJSONKeys
+// and ExistingJSONKeys - use a default Checker that is configured by
+// SetKeysToIgnore, SetMembersToIgnore, IgnoreOmitemptyTag and
+// SetNullsAsMissing.  Those setters
+// are NOT safe to call while the package level functions are in use by
+// other goroutines.
+type Checker struct {
+	skipkeys     []pattern         // JSON keys to NOT validate
+	skipmembers  []pattern         // dot-notation struct fields that can be missing
+	omitemptyOK  bool              // accept "omitempty" struct tags
+	strictcase   bool              // JSON keys must match member names exactly
+	nullsmissing bool              // JSON keys with null values count as missing
+	deprecated   map[string]string // dot-notation members: replacement hint
+	pathstyle    PathStyle         // format of the listed paths
+}
+
+// An Option configures a Checker; see NewChecker.
+type Option func(*Checker)
+
+// NewChecker returns a Checker configured by the options.  With no options
+// the Checker has the package defaults: the JSON key "config" is not
+// validated, no struct members are ignored, and "omitempty" tags are
+// recognized.
+//
+//	c := checkjson.NewChecker(
+//		checkjson.KeysToIgnore("comment"),
+//		checkjson.MembersToIgnore("more.not"),
+//	)
+//	keys, err := c.UnknownJSONKeys(data, &cfg)
+func NewChecker(opts ...Option) *Checker {
+	c := &Checker{
+		skipkeys:    []pattern{{"**", "config"}},
+		skipmembers: []pattern{},
+		omitemptyOK: true,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// KeysToIgnore sets the list of JSON keys that the Checker should not
+// validate as exported struct fields; it replaces the default "config" key.
+// Calling KeysToIgnore with no arguments validates all keys.  The semantics
+// are those of SetKeysToIgnore.
+func KeysToIgnore(s ...string) Option {
+	return func(c *Checker) {
+		c.skipkeys = make([]pattern, len(s))
+		for i, v := range s {
+			// a single key is ignored wherever it occurs
+			if !strings.Contains(v, ".") {
+				v = "**." + v
+			}
+			c.skipkeys[i] = compilePattern(v)
+		}
+	}
+}
+
+// MembersToIgnore sets the list of exported struct field names that the
+// Checker should not look for as keys in the JSON object.  The semantics
+// are those of SetMembersToIgnore.
+func MembersToIgnore(s ...string) Option {
+	return func(c *Checker) {
+		c.skipmembers = make([]pattern, len(s))
+		for i, v := range s {
+			c.skipmembers[i] = compilePattern(v)
+		}
+	}
+}
+
+// OmitemptyTag determines whether the Checker recognizes `json:",omitempty"`
+// struct tags; the default is true.  The semantics are those of
+// IgnoreOmitemptyTag.
+func OmitemptyTag(ok bool) Option {
+	return func(c *Checker) {
+		c.omitemptyOK = ok
+	}
+}
+
+// StrictCase determines whether the Checker matches JSON keys with struct
+// members case-sensitively, as encoding/json v2 and many other decoders do;
+// the default is false, matching keys case-insensitively as encoding/json
+// does.  In strict-case mode a JSON key must be the member's JSON tag or, if
+// it has none, the field name, exactly.  A key that matches only if case is
+// ignored - e.g., "whyNot" for `json:"whynot"` - is reported as a case
+// mismatch with the member's spelling: in Report.CaseMismatched and as a
+// CaseMismatchError.  It is not listed as an unknown key, but the member is
+// listed as missing, since it will not be set.
+func StrictCase(ok bool) Option {
+	return func(c *Checker) {
+		c.strictcase = ok
+	}
+}
+
+// NullsAsMissing determines whether the Checker treats a JSON key with the
+// value null as if it were not in the JSON object, so that its member is
+// listed as missing rather than existing; the default is false.  The
+// semantics are those of SetNullsAsMissing.
+func NullsAsMissing(ok bool) Option {
+	return func(c *Checker) {
+		c.nullsmissing = ok
+	}
+}
+
+// DeprecatedKeys sets the registry of deprecated struct members for the
+// Checker, in addition to those tagged `checkjson:"deprecated=hint"`.  The
+// semantics are those of SetDeprecatedKeys.
+func DeprecatedKeys(m map[string]string) Option {
+	return func(c *Checker) {
+		c.deprecated = make(map[string]string, len(m))
+		for k, v := range m {
+			c.deprecated[strings.ToLower(k)] = v
+		}
+	}
+}
+
+// PathFormat sets the format of the paths of the JSON keys and struct
+// members that the Checker lists; the default is DotNotation.  Other
+// formats locate each value exactly: array elements are numbered from 0
+// and included in the member paths, and keys are as they are in the JSON
+// object - the JSON name for a missing member.  E.g., for the key "json"
+// in the 2nd element of the array "not":
+//
+//	DotNotation  not.2.json
+//	JSONPointer  /not/1/json
+//	JSONPath     $.not[1].json
+//	GoFieldPath  Not[1].JSON
+//
+// The format applies to all of the lists: those returned by UnknownJSONKeys,
+// MissingJSONKeys and ExistingJSONKeys, and those in the Report, and to
+// the paths in the messages of errors and warnings - e.g., "missing required
+// JSON key: /tls/cert_file".  The errors and warnings hold the Path of the
+// value, which can be listed in any of the formats.
+func PathFormat(s PathStyle) Option {
+	return func(c *Checker) {
+		c.pathstyle = s
+	}
+}
+
+// std is the Checker used by the package level functions.
+var std = NewChecker()
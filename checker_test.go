@@ -0,0 +1,123 @@
+package checkjson
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestCheckerDefaults(t *testing.T) {
+	fmt.Println("===================== TestCheckerDefaults ...")
+
+	type test struct {
+		Ok  bool
+		Why string
+	}
+	tv := test{}
+	c := NewChecker()
+	data := []byte(`{"ok":true,"why":"it's a test","config":"test"}`)
+	if err := c.Validate(data, tv); err != nil {
+		t.Fatal(err)
+	}
+
+	data = []byte(`{"ok":true}`)
+	mems, err := c.MissingJSONKeys(data, tv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(mems) != "[Why]" {
+		t.Fatal("missing keys:", mems)
+	}
+	mems, err = c.ExistingJSONKeys(data, tv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(mems) != "[Ok]" {
+		t.Fatal("existing keys:", mems)
+	}
+}
+
+func TestCheckerOptions(t *testing.T) {
+	fmt.Println("===================== TestCheckerOptions ...")
+
+	type test2 struct {
+		Maybe bool
+	}
+	type test struct {
+		Ok     bool
+		Why    test2
+		Whynot string `json:",omitempty"`
+	}
+	tv := test{}
+	data := []byte(`{"ok":true, "why":{"maybe":true,"maybenot":false}, "not":"I don't know"}`)
+
+	c := NewChecker(KeysToIgnore("why.maybenot", "not"))
+	keys, err := c.UnknownJSONKeys(data, tv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 0 {
+		t.Fatal("unknown keys:", keys)
+	}
+	// the default Checker is not affected
+	keys, err = UnknownJSONKeys(data, tv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 {
+		t.Fatal("unknown keys:", keys)
+	}
+
+	c = NewChecker(MembersToIgnore("why"), OmitemptyTag(false))
+	data = []byte(`{"ok":true}`)
+	mems, err := c.MissingJSONKeys(data, tv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(mems) != "[Whynot]" {
+		t.Fatal("missing keys:", mems)
+	}
+	mems, err = MissingJSONKeys(data, tv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(mems) != "[Why]" {
+		t.Fatal("missing keys:", mems)
+	}
+}
+
+func TestCheckerConcurrent(t *testing.T) {
+	fmt.Println("===================== TestCheckerConcurrent ...")
+
+	type test struct {
+		Ok  bool
+		Why string
+	}
+	tv := test{}
+	data := []byte(`{"ok":true,"why":"it's a test","cfg":true,"comment":"none"}`)
+	cfg := NewChecker(KeysToIgnore("cfg", "comment"))
+	none := NewChecker(KeysToIgnore())
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 200)
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := cfg.Validate(data, tv); err != nil {
+				errs <- err
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if err := none.Validate(data, tv); err == nil {
+				errs <- fmt.Errorf("didn't catch 'cfg' key error")
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,122 @@
+// conflicts.go - struct members with the same JSON name
+// Copyright © 2016-2019 Charles Banning.  All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package checkjson
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// A FieldConflict is a warning that members of a struct have the same JSON
+// name, so that encoding/json can't decode a JSON key to all of them.  As
+// with encoding/json, of the members with the same name the one that is
+// least deeply embedded wins, then the one with a JSON tag; if there's still
+// more than one, they cancel out and none of them can be decoded.  Members
+// whose JSON names differ only in case are also reported: a JSON key that
+// matches none of the names exactly is decoded to the first of them.
+type FieldConflict struct {
+	Type     reflect.Type // the struct type
+	Name     string       // JSON name of the winning member, or of the members that cancel out
+	Members  []string     // Go field paths of the members - e.g., "Base.ID" - in struct definition order
+	Winner   string       // the member that is decoded to; "" if the members cancel out
+	CaseOnly bool         // the JSON names differ only in case
+}
+
+func (c *FieldConflict) Error() string {
+	switch {
+	case c.CaseOnly:
+		return fmt.Sprintf("struct %s: JSON names of members %s differ only in case - keys that match none exactly are decoded to: %s",
+			c.Type, strings.Join(c.Members, ", "), c.Winner)
+	case c.Winner == "":
+		return fmt.Sprintf("struct %s: members %s have the JSON name: %s - they cancel out and none is decoded",
+			c.Type, strings.Join(c.Members, ", "), c.Name)
+	}
+	var hidden []string
+	for _, m := range c.Members {
+		if m != c.Winner {
+			hidden = append(hidden, m)
+		}
+	}
+	return fmt.Sprintf("struct %s: member %s hides %s for JSON name: %s",
+		c.Type, c.Winner, strings.Join(hidden, ", "), c.Name)
+}
+
+// FieldConflicts returns the struct member conflicts for 'val' and the
+// struct types of its members, however deeply nested.  Unlike the
+// conflicts listed in Report.Warnings it doesn't depend on the JSON
+// object, so it can be used to check struct definitions in tests.
+func FieldConflicts(val interface{}) []*FieldConflict {
+	var conflicts []*FieldConflict
+	if val == nil {
+		return conflicts
+	}
+	visited := make(map[reflect.Type]bool)
+	var visit func(t reflect.Type)
+	visit = func(t reflect.Type) {
+		for {
+			if visited[t] || unmarshaler(t) {
+				return
+			}
+			visited[t] = true
+			switch t.Kind() {
+			case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+				t = t.Elem()
+				continue
+			case reflect.Struct:
+				sf := cachedTypeFields(t)
+				conflicts = append(conflicts, sf.conflicts...)
+				for i := range sf.list {
+					if !sf.list[i].ignored {
+						visit(sf.list[i].typ)
+					}
+				}
+			}
+			return
+		}
+	}
+	visit(reflect.TypeOf(val))
+	return conflicts
+}
+
+// newConflict returns the conflict for the 'fields' of 't' with the same
+// JSON name; 'ok' is whether the 'dominant' field wins.
+func newConflict(t reflect.Type, fields []field, dominant field, ok bool) *FieldConflict {
+	c := &FieldConflict{Type: t, Name: fields[0].jsonName()}
+	if ok {
+		c.Winner = goPath(t, dominant.index)
+	}
+	// fields are sorted by depth; list them in definition order, once each -
+	// a struct embedded twice at the same depth is listed twice in 'fields'
+	sorted := make([]field, len(fields))
+	copy(sorted, fields)
+	sort.Slice(sorted, func(i, j int) bool {
+		return lessIndex(sorted[i].index, sorted[j].index)
+	})
+	for _, f := range sorted {
+		p := goPath(t, f.index)
+		if len(c.Members) == 0 || c.Members[len(c.Members)-1] != p {
+			c.Members = append(c.Members, p)
+		}
+	}
+	return c
+}
+
+// goPath returns the Go field path for the index sequence 'index' of 't';
+// e.g., "Base.ID".
+func goPath(t reflect.Type, index []int) string {
+	names := make([]string, len(index))
+	for i, x := range index {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		sf := t.Field(x)
+		names[i] = sf.Name
+		t = sf.Type
+	}
+	return strings.Join(names, ".")
+}
@@ -0,0 +1,102 @@
+package checkjson
+
+import (
+	"fmt"
+	"testing"
+)
+
+type cfTagged struct {
+	ID string `json:"ID"`
+}
+
+type cfUntagged struct {
+	ID   string
+	Port int
+}
+
+type cfHost struct {
+	Addr string
+}
+
+// cfServer has each kind of conflict.
+type cfServer struct {
+	embBase             // ID and Name - cancel out with embOther.ID
+	embOther            // ID and Other
+	cfUntagged          // ID at depth 2, hidden; Port hidden by Port below
+	Port       int      // hides cfUntagged.Port
+	Addr       string   `json:"addr"`
+	ADDR       string   // differs only in case from "addr"
+	Hosts      []cfHost // checked through the slice
+}
+
+type cfNested struct {
+	Servers map[string]*cfServer
+}
+
+func TestFieldConflicts(t *testing.T) {
+	fmt.Println("===================== TestFieldConflicts ...")
+
+	conflicts := FieldConflicts(new(cfNested))
+	var got []string
+	for _, c := range conflicts {
+		fmt.Println(c)
+		got = append(got, fmt.Sprintf("%s %v %q %v", c.Name, c.Members, c.Winner, c.CaseOnly))
+	}
+	want := `[ID [embBase.ID embOther.ID cfUntagged.ID] "" false Port [cfUntagged.Port Port] "Port" false addr [Addr ADDR] "Addr" true]`
+	if s := fmt.Sprint(got); s != want {
+		t.Fatal("conflicts:", s, "!=", want)
+	}
+
+	// encoding/json agrees
+	data := []byte(`{"id":"a"}`)
+	if err := decodeStrict(data, new(cfServer)); err == nil {
+		t.Fatal("encoding/json decoded id")
+	}
+	if err := Validate(data, new(cfServer)); err == nil {
+		t.Fatal("no error returned")
+	}
+
+	if c := FieldConflicts(new(cfHost)); len(c) != 0 {
+		t.Fatal("conflicts:", c)
+	}
+	if c := FieldConflicts(struct{ cfTagged }{}); len(c) != 0 {
+		t.Fatal("conflicts:", c)
+	}
+	// a tagged member at the same depth wins
+	type tagWins struct {
+		cfTagged
+		cfUntagged
+	}
+	c := FieldConflicts(tagWins{})
+	if len(c) != 1 || c[0].Winner != "cfTagged.ID" {
+		t.Fatal("conflicts:", c)
+	}
+	fmt.Println(c[0])
+}
+
+func TestConflictWarnings(t *testing.T) {
+	fmt.Println("===================== TestConflictWarnings ...")
+
+	data := []byte(`{"servers":{"a":{"port":1},"b":{"addr":"x"}}}`)
+	r, err := Check(data, new(cfNested))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// cfServer is checked twice, but its conflicts are reported once
+	if len(r.Warnings) != 3 {
+		t.Fatal("warnings:", r.Warnings)
+	}
+	for _, w := range r.Warnings {
+		if _, ok := w.(*FieldConflict); !ok {
+			t.Fatalf("not a *FieldConflict: %v", w)
+		}
+	}
+
+	r, err = Check(data, new(mapConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Warnings) != 0 {
+		t.Fatal("warnings:", r.Warnings)
+	}
+}
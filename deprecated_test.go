@@ -0,0 +1,92 @@
+package checkjson
+
+import (
+	"fmt"
+	"testing"
+)
+
+type dpTLS struct {
+	Cert     string `json:"cert" checkjson:"deprecated=use tls.cert_file"`
+	CertFile string `json:"cert_file"`
+	Key      string `json:"key"`
+	KeyFile  string `json:"key_file"`
+	Pass     string `json:"pass" checkjson:"alias=pw,deprecated=use pass_file, or the PASS variable"`
+}
+
+type dpConfig struct {
+	TLS     dpTLS `json:"tls"`
+	Timeout int   `checkjson:"deprecated"`
+	Servers []struct {
+		Addr string
+		Host string
+	}
+}
+
+func TestDeprecatedKeys(t *testing.T) {
+	fmt.Println("===================== TestDeprecatedKeys ...")
+
+	data := []byte(`{
+		"tls":{"cert":"a.pem","key":"a.key","ca":"x"},
+		"timeout":5,
+		"servers":[{"addr":"a"},{"host":"b"}]
+	}`)
+	c := NewChecker(DeprecatedKeys(map[string]string{
+		"TLS.key":      "use tls.key_file",
+		"servers.addr": "use servers.host",
+	}))
+	keys, warns, err := c.UnknownJSONKeysWithWarnings(data, new(dpConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(keys); s != "[tls.ca]" {
+		t.Fatal("unknown:", s)
+	}
+	var got []string
+	for _, w := range warns {
+		dw, ok := w.(*DeprecatedKeyWarning)
+		if !ok {
+			t.Fatalf("not a DeprecatedKeyWarning: %v", w)
+		}
+		got = append(got, dw.Error())
+	}
+	want := "[deprecated JSON key: tls.cert - use tls.cert_file " +
+		"deprecated JSON key: tls.key - use tls.key_file " +
+		"deprecated JSON key: timeout " +
+		"deprecated JSON key: servers.1.addr - use servers.host]"
+	if s := fmt.Sprint(got); s != want {
+		t.Fatal("warnings:", s, "!=", want)
+	}
+
+	// deprecated keys aren't errors, and deprecated members aren't missing
+	data = []byte(`{"tls":{"cert":"a.pem","cert_file":"a.pem","key_file":"a.key"},"servers":[{"host":"a"}]}`)
+	if err := c.Validate(data, new(dpConfig)); err != nil {
+		t.Fatal(err)
+	}
+	mems, err := c.MissingJSONKeys(data, new(dpConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mems) != 0 {
+		t.Fatal("missing:", mems)
+	}
+
+	// a tagged hint can have commas
+	_, warns, err = c.UnknownJSONKeysWithWarnings([]byte(`{"tls":{"pw":"a"}}`), new(dpConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(warns); s != "[deprecated JSON key: tls.pw - use pass_file, or the PASS variable]" {
+		t.Fatal("warnings:", s)
+	}
+
+	// the package level registry
+	SetDeprecatedKeys(map[string]string{"tls.key": "use tls.key_file"})
+	defer SetDeprecatedKeys(nil)
+	_, warns, err = UnknownJSONKeysWithWarnings([]byte(`{"tls":{"key":"a"}}`), new(dpConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(warns); s != "[deprecated JSON key: tls.key - use tls.key_file]" {
+		t.Fatal("warnings:", s)
+	}
+}
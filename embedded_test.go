@@ -0,0 +1,210 @@
+package checkjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type embBase struct {
+	ID   string
+	Name string `json:"Name"`
+}
+
+type EmbMore struct {
+	Name  string
+	Notes string
+}
+
+type embedded struct {
+	embBase
+	*EmbMore
+	Extra int
+}
+
+type embTagged struct {
+	Base  embBase `json:"base"`
+	Extra int
+}
+
+type embCycleA struct {
+	*embCycleB
+	A int
+}
+
+type embCycleB struct {
+	*embCycleA
+	B int
+}
+
+type embConflict struct {
+	embBase
+	embOther
+}
+
+type embOther struct {
+	ID    string
+	Other string
+}
+
+// decodeStrict reports whether encoding/json can decode 'data' to 'v'
+// without unknown fields.
+func decodeStrict(data []byte, v interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.DisallowUnknownFields()
+	return d.Decode(v)
+}
+
+func TestEmbeddedFields(t *testing.T) {
+	fmt.Println("===================== TestEmbeddedFields ...")
+
+	fields := cachedTypeFields(reflect.TypeOf(embedded{})).list
+	var names []string
+	for _, f := range fields {
+		names = append(names, f.jsonName())
+	}
+	// embBase.Name is tagged, so it dominates EmbMore.Name
+	if s := fmt.Sprint(names); s != "[ID Name Notes Extra]" {
+		t.Fatal("fields:", s)
+	}
+
+	data := []byte(`{"id":"a","name":"b","notes":"c","extra":1}`)
+	if err := decodeStrict(data, new(embedded)); err != nil {
+		t.Fatal("encoding/json:", err)
+	}
+	r, err := Check(data, new(embedded))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Unknown) != 0 {
+		t.Fatal("unknown:", r.Unknown)
+	}
+	if s := fmt.Sprint(r.Existing); s != "[ID Name Notes Extra]" {
+		t.Fatal("existing:", s)
+	}
+
+	data = []byte(`{"name":"b","embbase":{"id":"a"}}`)
+	if err := decodeStrict(data, new(embedded)); err == nil {
+		t.Fatal("encoding/json decoded embbase")
+	}
+	r, err = Check(data, new(embedded))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(r.Unknown); s != "[embbase]" {
+		t.Fatal("unknown:", s)
+	}
+	if s := fmt.Sprint(r.Missing); s != "[ID Notes Extra]" {
+		t.Fatal("missing:", s)
+	}
+}
+
+func TestEmbeddedTagged(t *testing.T) {
+	fmt.Println("===================== TestEmbeddedTagged ...")
+
+	data := []byte(`{"base":{"id":"a","name":"b"},"extra":1}`)
+	if err := decodeStrict(data, new(embTagged)); err != nil {
+		t.Fatal("encoding/json:", err)
+	}
+	if err := Validate(data, new(embTagged)); err != nil {
+		t.Fatal(err)
+	}
+
+	data = []byte(`{"id":"a","extra":1}`)
+	if err := decodeStrict(data, new(embTagged)); err == nil {
+		t.Fatal("encoding/json decoded id")
+	}
+	if err := Validate(data, new(embTagged)); err == nil {
+		t.Fatal("no error returned")
+	} else {
+		fmt.Println("err ok:", err)
+	}
+}
+
+func TestEmbeddedConflict(t *testing.T) {
+	fmt.Println("===================== TestEmbeddedConflict ...")
+
+	// embBase.ID and embOther.ID are at the same depth, so neither is decoded
+	data := []byte(`{"id":"a","name":"b","other":"c"}`)
+	if err := decodeStrict(data, new(embConflict)); err == nil {
+		t.Fatal("encoding/json decoded id")
+	}
+	keys, err := UnknownJSONKeys(data, new(embConflict))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(keys); s != "[id]" {
+		t.Fatal("unknown:", s)
+	}
+	mems, err := MissingJSONKeys([]byte(`{}`), new(embConflict))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(mems); s != "[name Other]" {
+		t.Fatal("missing:", s)
+	}
+}
+
+func TestEmbeddedCycle(t *testing.T) {
+	fmt.Println("===================== TestEmbeddedCycle ...")
+
+	data := []byte(`{"a":1,"b":2,"c":3}`)
+	keys, err := UnknownJSONKeys(data, &embCycleA{embCycleB: new(embCycleB)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(keys); s != "[c]" {
+		t.Fatal("unknown:", s)
+	}
+
+	// encoding/json can't set the nil *embCycleB for "b"
+	keys, err = UnknownJSONKeys(data, new(embCycleA))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(keys); s != "[b c]" {
+		t.Fatal("unknown:", s)
+	}
+}
+
+type embHidden struct {
+	*embBase
+	Z int
+}
+
+func TestEmbeddedNilPointer(t *testing.T) {
+	fmt.Println("===================== TestEmbeddedNilPointer ...")
+
+	data := []byte(`{"id":"a","z":1}`)
+	if jerr := json.Unmarshal(data, new(embHidden)); jerr == nil {
+		t.Fatal("encoding/json decoded through a nil embedded pointer")
+	}
+	err := Validate(data, new(embHidden))
+	var eerr *EmbeddedPointerError
+	if !errors.As(err, &eerr) {
+		t.Fatalf("not an EmbeddedPointerError: %v", err)
+	}
+	if eerr.Path.String() != "id" || eerr.Type != reflect.TypeOf(embBase{}) {
+		t.Fatalf("error: %+v", eerr)
+	}
+	fmt.Println("err ok:", err)
+
+	r, err := Check(data, new(embHidden))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(r.Unknown, r.Missing); s != "[id] [ID name]" {
+		t.Fatal("unknown, missing:", s)
+	}
+
+	// a pointer that's set is decoded into
+	if err := Validate(data, &embHidden{embBase: new(embBase)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Validate([]byte(`{"z":1}`), new(embHidden)); err != nil {
+		t.Fatal(err)
+	}
+}
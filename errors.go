@@ -0,0 +1,329 @@
+// errors.go - errors returned by Validate
+// Copyright © 2016-2019 Charles Banning.  All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package checkjson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// An UnknownKeyError reports a JSON key that doesn't correspond to an
+// exported member of the struct.
+type UnknownKeyError struct {
+	Path        Path         // path to the JSON key
+	Type        reflect.Type // struct type without a member for the key
+	Suggestions []string     // JSON names of the members the key may be meant for, best first
+}
+
+func (e *UnknownKeyError) Error() string {
+	if len(e.Suggestions) > 0 {
+		return fmt.Sprintf("no member for JSON key: %s - did you mean: %s?",
+			e.Path[len(e.Path)-1].Key, strings.Join(e.Suggestions, ", "))
+	}
+	return fmt.Sprintf("no member for JSON key: %s", e.Path[len(e.Path)-1].Key)
+}
+
+// A MissingKeyError reports a struct member tagged `checkjson:"required"`
+// that has no JSON key in the JSON object; see RequireKeys.
+type MissingKeyError struct {
+	Path Path         // path to where the JSON key should be
+	Type reflect.Type // struct type with the member
+
+	style PathStyle // the Checker's path format, for the message
+}
+
+func (e *MissingKeyError) Error() string {
+	return fmt.Sprintf("missing required JSON key: %s", e.Path.format(e.style, Path.String))
+}
+
+// An AliasError reports two JSON keys in a JSON object for the same struct
+// member, at least one of them an alias - see RewriteAliases - so it isn't
+// clear which value is meant.
+type AliasError struct {
+	Path  Path         // path to the second JSON key
+	Type  reflect.Type // struct type with the member
+	Other string       // the first JSON key, as it is in the JSON object
+}
+
+func (e *AliasError) Error() string {
+	k := e.Path[len(e.Path)-1]
+	return fmt.Sprintf("JSON keys: %s and %s - are for the same member: %s", e.Other, k.Key, k.field.name)
+}
+
+// An EmbeddedPointerError reports a JSON key for a member of an embedded
+// struct that encoding/json can't decode: the embedded pointer to the
+// struct is nil and the struct type is unexported, so it can't be set.
+type EmbeddedPointerError struct {
+	Path Path         // path to the JSON key
+	Type reflect.Type // unexported struct type of the embedded pointer
+}
+
+func (e *EmbeddedPointerError) Error() string {
+	return fmt.Sprintf("JSON key: %s - can't be set: nil embedded pointer to unexported struct: %s",
+		e.Path[len(e.Path)-1].Key, e.Type)
+}
+
+// A CaseMismatchError reports a JSON key that matches the JSON name of a
+// struct member only if case is ignored; see StrictCase.
+type CaseMismatchError struct {
+	Path Path         // path to the JSON key
+	Type reflect.Type // struct type with the member
+	Name string       // the member's JSON tag or field name
+}
+
+func (e *CaseMismatchError) Error() string {
+	return fmt.Sprintf("JSON key: %s - does not match the case of member: %s", e.Path[len(e.Path)-1].Key, e.Name)
+}
+
+// A TypeMismatchError reports a JSON value that can't be decoded to the Go
+// type of its member; e.g., the JSON string "8080" for a member of type int.
+type TypeMismatchError struct {
+	Path  Path         // path to the JSON value
+	Type  reflect.Type // Go type of the member
+	Kind  string       // kind of the JSON value: "bool", "number", "string", "array" or "object"
+	Value string       // the JSON value; arrays and objects are abbreviated - "[...]", "{...}"
+}
+
+func (e *TypeMismatchError) Error() string {
+	return fmt.Sprintf("JSON %s value: %s - can't be decoded to Go type: %s", e.Kind, e.Value, e.Type)
+}
+
+// A StringOptionError reports a JSON value for a member with the ",string"
+// tag option that isn't a JSON string wrapping a value of the member's type;
+// e.g., 8080 or "80a" for `json:"port,string"` and type int.
+type StringOptionError struct {
+	Path  Path         // path to the JSON value
+	Type  reflect.Type // Go type of the member
+	Kind  string       // kind of the JSON value
+	Value string       // the JSON value; arrays and objects are abbreviated
+	Err   error        // decoding error for the wrapped value; nil if the value isn't a string
+}
+
+func (e *StringOptionError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("JSON %s value: %s - not a valid \",string\" value for Go type: %s: %s", e.Kind, e.Value, e.Type, e.Err)
+	}
+	return fmt.Sprintf("JSON %s value: %s - not a valid \",string\" value for Go type: %s", e.Kind, e.Value, e.Type)
+}
+
+func (e *StringOptionError) Unwrap() error {
+	return e.Err
+}
+
+// The reasons for a NumberError.
+var (
+	ErrNotInteger = errors.New("not an integer")
+	ErrNegative   = errors.New("negative")
+	ErrOutOfRange = errors.New("out of range")
+)
+
+// A NumberError reports a JSON number that can't be decoded to the numeric
+// Go type of its member; e.g., 300 for a uint8, -1 for a uint or 1.5 for an
+// int.  Err is ErrNotInteger, ErrNegative or ErrOutOfRange.
+type NumberError struct {
+	Path  Path         // path to the JSON value
+	Type  reflect.Type // Go type of the member
+	Value string       // the JSON number, as it is in the JSON object
+	Err   error        // the reason
+}
+
+func (e *NumberError) Error() string {
+	return fmt.Sprintf("JSON number value: %s - %s for Go type: %s", e.Value, e.Err, e.Type)
+}
+
+func (e *NumberError) Unwrap() error {
+	return e.Err
+}
+
+// A PrecisionWarning reports a JSON integer whose magnitude is greater than
+// 2^53, so it can't be represented exactly by a float64.  It is listed in
+// Report.Warnings if the member is an interface{}, which encoding/json
+// decodes numbers to as float64 values - unless Decoder.UseNumber is used -
+// or if the member is a 64-bit integer, which encoding/json decodes exactly
+// but other decoders - e.g., JavaScript's - decode via float64.
+type PrecisionWarning struct {
+	Path  Path         // path to the JSON value
+	Type  reflect.Type // Go type of the member
+	Value string       // the JSON number, as it is in the JSON object
+
+	style PathStyle // the Checker's path format, for the message
+}
+
+func (e *PrecisionWarning) Error() string {
+	p := e.Path.format(e.style, Path.String)
+	if e.Type.Kind() == reflect.Interface {
+		return fmt.Sprintf("JSON number value: %s for key: %s - loses precision as a float64 for Go type: %s", e.Value, p, e.Type)
+	}
+	return fmt.Sprintf("JSON number value: %s for key: %s - loses precision if decoded via float64 for Go type: %s", e.Value, p, e.Type)
+}
+
+// A NullWarning reports a JSON null value for a member that isn't a pointer,
+// slice, map or interface, and doesn't implement json.Unmarshaler.
+// encoding/json leaves such a member unchanged - null doesn't set it to its
+// zero value.  It is listed in Report.Warnings.
+type NullWarning struct {
+	Path Path         // path to the JSON value
+	Type reflect.Type // Go type of the member
+
+	style PathStyle // the Checker's path format, for the message
+}
+
+func (e *NullWarning) Error() string {
+	return fmt.Sprintf("null on non-nullable field: %s of Go type: %s - the value is not changed",
+		e.Path.format(e.style, Path.String), e.Type)
+}
+
+// A DeprecatedKeyWarning reports a JSON key that is decoded to a deprecated
+// struct member, with the hint for its replacement.  It is listed in
+// Report.Warnings.
+type DeprecatedKeyWarning struct {
+	Path Path   // path to the JSON key
+	Hint string // e.g., "use tls.cert_file"; may be ""
+
+	style PathStyle // the Checker's path format, for the message
+}
+
+func (e *DeprecatedKeyWarning) Error() string {
+	p := e.Path.format(e.style, Path.String)
+	if e.Hint == "" {
+		return fmt.Sprintf("deprecated JSON key: %s", p)
+	}
+	return fmt.Sprintf("deprecated JSON key: %s - %s", p, e.Hint)
+}
+
+// A DuplicateKeyWarning reports a JSON key for a struct member that already
+// has a JSON key in the JSON object, e.g. a repeated key or one that differs
+// only in case.  encoding/json decodes both values to the member, so the
+// last value wins - or, for a struct, the values are merged.  It is listed
+// in Report.Warnings.
+type DuplicateKeyWarning struct {
+	Path  Path   // path to the repeated JSON key
+	Other string // the first JSON key, as it is in the JSON object
+
+	style PathStyle // the Checker's path format, for the message
+}
+
+func (e *DuplicateKeyWarning) Error() string {
+	return fmt.Sprintf("duplicate JSON key: %s - the member already has JSON key: %s",
+		e.Path.format(e.style, Path.String), e.Other)
+}
+
+// A NotObjectError reports a JSON value that should be an object - with
+// k:v pairs - because it is decoded to a struct or a map.
+type NotObjectError struct {
+	Path Path         // path to the JSON value
+	Type reflect.Type // struct or map type of the member
+}
+
+func (e *NotObjectError) Error() string {
+	name := e.Type.Name()
+	if name == "" {
+		name = e.Type.String()
+	}
+	return fmt.Sprintf("JSON object does not have k:v pairs for member: %s", name)
+}
+
+// A NotArrayError reports a JSON value that should be an array because it
+// is decoded to a slice or an array.
+type NotArrayError struct {
+	Path Path         // path to the JSON value
+	Type reflect.Type // slice or array type of the member
+}
+
+func (e *NotArrayError) Error() string {
+	return "JSON value not an array"
+}
+
+// An ArrayLengthError reports a JSON array with more elements than the
+// Go array it is decoded to; encoding/json drops the extra elements.
+type ArrayLengthError struct {
+	Path Path         // path to the JSON array
+	Type reflect.Type // array type of the member
+	Len  int          // number of elements in the JSON array
+}
+
+func (e *ArrayLengthError) Error() string {
+	return fmt.Sprintf("JSON array has %d elements - more than the %d of %s", e.Len, e.Type.Len(), e.Type)
+}
+
+// A MapKeyError reports a JSON key that can't be decoded to the key type
+// of a map member.
+type MapKeyError struct {
+	Path Path         // path to the JSON key
+	Type reflect.Type // map type of the member
+	Err  error        // the reason the key can't be decoded
+}
+
+func (e *MapKeyError) Error() string {
+	return fmt.Sprintf("JSON key: %s - not a valid %s map key: %s",
+		e.Path[len(e.Path)-1].Key, e.Type.Key(), e.Err.Error())
+}
+
+func (e *MapKeyError) Unwrap() error {
+	return e.Err
+}
+
+// A SyntaxError is a JSON syntax error with the JSON text that precedes it
+// and its position; see ResolveJSONError.
+type SyntaxError struct {
+	Err     *json.SyntaxError
+	Context string   // the JSON text from the last key up to the error
+	Pos     Position // of the byte where parsing stopped
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s - at: %s (stopped at line: %d, column: %d - position: %d)",
+		e.Err.Error(), e.Context, e.Pos.Line, e.Pos.Column, e.Err.Offset)
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// wrap adds the context of the JSON keys and array elements of 'p' to 'err'.
+func wrap(p Path, err error) error {
+	for i := len(p) - 1; i >= 0; i-- {
+		if p[i].Index >= 0 {
+			err = &elementError{p[i].Index, err}
+		} else {
+			err = &subkeyError{p[i].Key, err}
+		}
+	}
+	return err
+}
+
+// subkeyError provides the context of the JSON key for an error in the
+// key's value.
+type subkeyError struct {
+	key string
+	err error
+}
+
+func (e *subkeyError) Error() string {
+	return fmt.Sprintf("checking subkeys of JSON key: %s - %s", e.key, e.err.Error())
+}
+
+func (e *subkeyError) Unwrap() error {
+	return e.err
+}
+
+// elementError provides the context of the JSON array element for an error
+// in the element.
+type elementError struct {
+	index int
+	err   error
+}
+
+func (e *elementError) Error() string {
+	return fmt.Sprintf("[array element #%d] %s", e.index+1, e.err.Error())
+}
+
+func (e *elementError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,60 @@
+package checkjson
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestValidateErrors(t *testing.T) {
+	fmt.Println("===================== TestValidateErrors ...")
+
+	s := new(struct1)
+
+	data := []byte(`{"this":"is","not":[{"a":"simple"},{"json":"object","else":false}]}`)
+	err := Validate(data, s)
+	var uerr *UnknownKeyError
+	if !errors.As(err, &uerr) {
+		t.Fatalf("not an UnknownKeyError: %#v", err)
+	}
+	if uerr.Path.String() != "not.2.else" {
+		t.Fatal("path:", uerr.Path)
+	}
+	if len(uerr.Path) != 3 || uerr.Path[1].Index != 1 || uerr.Path[2].Key != "else" {
+		t.Fatalf("path segments: %#v", uerr.Path)
+	}
+	if uerr.Type != reflect.TypeOf(struct2{}) {
+		t.Fatal("type:", uerr.Type)
+	}
+	if !errors.Is(err, uerr) {
+		t.Fatal("errors.Is failed")
+	}
+	want := "checking subkeys of JSON key: not - [array element #2] no member for JSON key: else"
+	if err.Error() != want {
+		t.Fatal(err.Error(), "!=", want)
+	}
+	fmt.Println("err ok:", err)
+
+	data = []byte(`{"this":"is","not":{"a":"simple"}}`)
+	err = Validate(data, s)
+	var aerr *NotArrayError
+	if !errors.As(err, &aerr) {
+		t.Fatalf("not a NotArrayError: %#v", err)
+	}
+	if aerr.Path.String() != "not" || aerr.Type != reflect.TypeOf([]struct2{}) {
+		t.Fatal("path:", aerr.Path, "type:", aerr.Type)
+	}
+	fmt.Println("err ok:", err)
+
+	data = []byte(`{"this":"is","is":["a","json"]}`)
+	err = Validate(data, s)
+	var oerr *NotObjectError
+	if !errors.As(err, &oerr) {
+		t.Fatalf("not a NotObjectError: %#v", err)
+	}
+	if oerr.Path.String() != "is" || oerr.Type != reflect.TypeOf(struct2{}) {
+		t.Fatal("path:", oerr.Path, "type:", oerr.Type)
+	}
+	fmt.Println("err ok:", err)
+}
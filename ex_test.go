@@ -51,7 +51,7 @@ func TestEx(t *testing.T) {
 	if err != nil {
 		t.Fatal("UnknownJSONKeys:", err)
 	}
-	want = `[elem2.notes elem4]`
+	want = `[elem2.notes elem2.strange.irr elem2.strange.strange_ex elem4]`
 	s = fmt.Sprint(result)
 	if s != want {
 		t.Fatal("UnknownJSONKeys", s, "!=", want)
@@ -0,0 +1,487 @@
+// fields.go - struct member metadata
+// Copyright © 2016-2019 Charles Banning.  All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// typeFields, dominantField, lessIndex and isValidTag are adapted from
+// encoding/json/encode.go, and foldName and foldRune from
+// encoding/json/fold.go:
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the NOTICE file.
+
+package checkjson
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// field describes an exported struct member as seen by encoding/json.
+// The fields of embedded structs are promoted to the struct that embeds them,
+// as with encoding/json, and 'index' is the index sequence for the member.
+type field struct {
+	name       string       // Go field name
+	tag        string       // JSON tag name, lower cased; "" if there isn't one
+	rawtag     string       // JSON tag name as written in the struct definition
+	index      []int        // index sequence in the struct - see reflect.Value.FieldByIndex
+	typ        reflect.Type // field type
+	omitempty  bool         // `json:",omitempty"`
+	quoted     bool         // `json:",string"` - the value is wrapped in a JSON string
+	norecurse  bool         // `checkjson:"norecurse"`
+	required   bool         // `checkjson:"required"`
+	optional   bool         // `checkjson:"optional"`
+	deprecated bool         // `checkjson:"deprecated=hint"`
+	hint       string       // replacement hint for a deprecated member
+	aliases    []string     // `checkjson:"alias=name1|name2"`
+	ignored    bool         // `json:"-"`
+}
+
+// jsonName is the name encoding/json uses for the field.
+func (f *field) jsonName() string {
+	if len(f.rawtag) > 0 {
+		return f.rawtag
+	}
+	return f.name
+}
+
+// key is used to match JSON object keys with the field: the JSON name with
+// case folded as encoding/json does - see foldName.
+func (f *field) key() string {
+	return foldName(f.jsonName())
+}
+
+// label is used in the dot-notation member paths: the JSON tag, if any,
+// otherwise the field name.
+func (f *field) label() string {
+	if len(f.tag) > 0 {
+		return f.tag
+	}
+	return f.name
+}
+
+// structFields is the metadata for a struct type.
+type structFields struct {
+	list      []field           // in definition order
+	exact     map[string]int    // JSON name: index in list
+	folded    map[string]int    // key(): index in list
+	conflicts []*FieldConflict  // members with the same JSON name
+	aliases   map[string]int    // alias: index in list
+	falias    map[string]string // folded alias: alias
+}
+
+var fieldCache sync.Map // map[reflect.Type]*structFields
+
+// cachedTypeFields is like typeFields but uses a cache, so the fields of a
+// struct type are only built once.  The fields of a type are built without
+// looking at the member types, so the metadata for a recursive struct
+// definition - e.g., type Node struct { Children []*Node } - is complete
+// once the fields of Node are built; the members are only scanned as far
+// as the JSON object goes.
+func cachedTypeFields(t reflect.Type) *structFields {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.(*structFields)
+	}
+	list, conflicts := typeFields(t)
+	sf := &structFields{
+		list:      list,
+		exact:     make(map[string]int, len(list)),
+		folded:    make(map[string]int, len(list)),
+		conflicts: conflicts,
+		aliases:   make(map[string]int),
+		falias:    make(map[string]string),
+	}
+	var folds map[int]*FieldConflict
+	for i := range list {
+		sf.exact[list[i].jsonName()] = i
+		for _, a := range list[i].aliases {
+			if _, ok := sf.aliases[a]; !ok {
+				sf.aliases[a] = i
+			}
+			if _, ok := sf.falias[foldName(a)]; !ok {
+				sf.falias[foldName(a)] = a
+			}
+		}
+		// as with encoding/json, the first folded match takes precedence
+		k := list[i].key()
+		j, ok := sf.folded[k]
+		if !ok {
+			sf.folded[k] = i
+			continue
+		}
+		if list[i].ignored || list[j].ignored {
+			continue
+		}
+		// names that differ only in case
+		if folds == nil {
+			folds = make(map[int]*FieldConflict)
+		}
+		c, ok := folds[j]
+		if !ok {
+			c = &FieldConflict{
+				Type:     t,
+				Name:     list[j].jsonName(),
+				Members:  []string{goPath(t, list[j].index)},
+				Winner:   goPath(t, list[j].index),
+				CaseOnly: true,
+			}
+			folds[j] = c
+			sf.conflicts = append(sf.conflicts, c)
+		}
+		c.Members = append(c.Members, goPath(t, list[i].index))
+	}
+	f, _ := fieldCache.LoadOrStore(t, sf)
+	return f.(*structFields)
+}
+
+// lookup returns the index in 'list' of the field that the JSON key 'k' is
+// decoded to, and the name of the field that 'k' matched.  As with
+// encoding/json that's the field with the JSON name 'k', else the first
+// field whose name matches 'k' if case is ignored; failing those, the field
+// with the alias 'k', else the one with an alias that matches 'k' if case is
+// ignored - see RewriteAliases.  If 'exact' is false the name was matched by
+// ignoring case.
+func (sf *structFields) lookup(k string) (i int, name string, exact, ok bool) {
+	if i, ok = sf.exact[k]; ok {
+		return i, k, true, true
+	}
+	fk := foldName(k)
+	if i, ok = sf.folded[fk]; ok {
+		return i, sf.list[i].jsonName(), false, true
+	}
+	if i, ok = sf.aliases[k]; ok {
+		return i, k, true, true
+	}
+	if a, ok := sf.falias[fk]; ok {
+		return sf.aliases[a], a, false, true
+	}
+	return 0, "", false, false
+}
+
+// foldName returns 's' with case folded as encoding/json does when it
+// matches a JSON key with a field name: foldName(x) == foldName(y) if and
+// only if strings.EqualFold(x, y).  So, e.g., the Kelvin sign 'K' matches
+// "k" and the long s 'ſ' matches "S", but 'İ' doesn't match "i", though
+// strings.ToLower would map it to "i".
+func foldName(s string) string {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r < utf8.RuneSelf {
+			if 'a' <= r && r <= 'z' {
+				r -= 'a' - 'A'
+			}
+			b = append(b, byte(r))
+			continue
+		}
+		b = utf8.AppendRune(b, foldRune(r))
+	}
+	return string(b)
+}
+
+// foldRune returns the smallest rune of all the runes in the same fold set.
+func foldRune(r rune) rune {
+	for {
+		r2 := unicode.SimpleFold(r)
+		if r2 <= r {
+			return r2
+		}
+		r = r2
+	}
+}
+
+// typeFields returns the exported fields of the struct type 't' in
+// definition order, applying the encoding/json rules for embedded structs:
+//
+//   - the exported fields of an embedded struct, or pointer to struct, are
+//     promoted to 't' - even if the embedded struct type is not exported;
+//   - an embedded struct with a JSON tag is treated as a named member;
+//   - of the fields with the same JSON name, the one at the shallowest depth
+//     of embedding wins, then the one with a JSON tag; if there's still more
+//     than one, none of them is decoded.
+//
+// Members with the JSON tag "-" are included, but flagged as ignored.
+// The members that are hidden or cancelled by the rules are returned as
+// conflicts.
+func typeFields(t reflect.Type) ([]field, []*FieldConflict) {
+	// Anonymous fields to explore at the current level and the next.
+	current := []field{}
+	next := []field{{typ: t}}
+
+	// Count of queued names for current level and the next.
+	var count, nextCount map[reflect.Type]int
+
+	// Types already visited at an earlier level; this stops embedded
+	// pointer cycles - type A struct{ *B }; type B struct{ *A }.
+	visited := map[reflect.Type]bool{}
+
+	var fields, ignored []field
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		count, nextCount = nextCount, map[reflect.Type]int{}
+
+		for _, f := range current {
+			if visited[f.typ] {
+				continue
+			}
+			visited[f.typ] = true
+
+			for i := 0; i < f.typ.NumField(); i++ {
+				sf := f.typ.Field(i)
+				if sf.Anonymous {
+					t := sf.Type
+					if t.Kind() == reflect.Ptr {
+						t = t.Elem()
+					}
+					if len(sf.PkgPath) > 0 && t.Kind() != reflect.Struct {
+						continue // unexported non-struct type
+					}
+					// unexported struct types may have exported fields
+				} else if len(sf.PkgPath) > 0 {
+					continue // field is NOT exported
+				}
+				index := make([]int, len(f.index)+1)
+				copy(index, f.index)
+				index[len(f.index)] = i
+
+				tags := strings.Split(sf.Tag.Get("json"), ",")
+				// handle ignore member JSON tag, "-"
+				if len(tags) == 1 && tags[0] == "-" {
+					ignored = append(ignored, field{name: sf.Name, index: index, typ: sf.Type, ignored: true})
+					continue
+				}
+				name := tags[0]
+				if !isValidTag(name) {
+					name = ""
+				}
+
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+
+				// Record found field and index sequence.
+				if name != "" || !sf.Anonymous || ft.Kind() != reflect.Struct {
+					nf := field{
+						name:   sf.Name,
+						rawtag: name,
+						tag:    strings.ToLower(name),
+						index:  index,
+						typ:    sf.Type,
+					}
+					// scan the checkjson tag options; a "deprecated=" hint
+					// is the rest of the tag, so it can have commas
+					for opts := sf.Tag.Get("checkjson"); len(opts) > 0; {
+						v := opts
+						if i := strings.IndexByte(opts, ','); i >= 0 && !strings.HasPrefix(opts, "deprecated=") {
+							v, opts = opts[:i], opts[i+1:]
+						} else {
+							opts = ""
+						}
+						switch v {
+						case "norecurse":
+							nf.norecurse = true
+						case "required":
+							nf.required = true
+						case "optional":
+							nf.optional = true
+						case "deprecated":
+							nf.deprecated = true
+						default:
+							if strings.HasPrefix(v, "deprecated=") {
+								nf.deprecated = true
+								nf.hint = strings.TrimPrefix(v, "deprecated=")
+							}
+							if strings.HasPrefix(v, "alias=") {
+								for _, a := range strings.Split(strings.TrimPrefix(v, "alias="), "|") {
+									if len(a) > 0 {
+										nf.aliases = append(nf.aliases, a)
+									}
+								}
+							}
+						}
+					}
+					if nf.required {
+						nf.optional = false
+					}
+					// scan rest of tags for "omitempty" and "string"
+					for _, v := range tags[1:] {
+						switch v {
+						case "omitempty":
+							nf.omitempty = true
+						case "string":
+							// only applies to scalar types, as with encoding/json
+							switch ft.Kind() {
+							case reflect.Bool,
+								reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+								reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+								reflect.Float32, reflect.Float64,
+								reflect.String:
+								nf.quoted = true
+							}
+						}
+					}
+					fields = append(fields, nf)
+					if count[f.typ] > 1 {
+						// If there were multiple instances, add a second,
+						// so that the annihilation code will see a duplicate.
+						fields = append(fields, fields[len(fields)-1])
+					}
+					continue
+				}
+
+				// Record new anonymous struct to explore in next round.
+				nextCount[ft]++
+				if nextCount[ft] == 1 {
+					next = append(next, field{name: ft.Name(), index: index, typ: ft})
+				}
+			}
+		}
+	}
+
+	// Sort by JSON name, breaking ties with depth, then with "name came
+	// from JSON tag", then with index sequence.
+	sort.Slice(fields, func(i, j int) bool {
+		x, y := fields[i], fields[j]
+		if x.jsonName() != y.jsonName() {
+			return x.jsonName() < y.jsonName()
+		}
+		if len(x.index) != len(y.index) {
+			return len(x.index) < len(y.index)
+		}
+		if (len(x.rawtag) > 0) != (len(y.rawtag) > 0) {
+			return len(x.rawtag) > 0
+		}
+		return lessIndex(x.index, y.index)
+	})
+
+	// Delete all fields that are hidden by the Go rules for embedded fields,
+	// except that fields with JSON tags are promoted.
+	var conflicts []*FieldConflict
+	out := fields[:0]
+	for advance, i := 0, 0; i < len(fields); i += advance {
+		// One iteration per name.
+		// Find the sequence of fields with the name of this first field.
+		name := fields[i].jsonName()
+		for advance = 1; i+advance < len(fields); advance++ {
+			if fields[i+advance].jsonName() != name {
+				break
+			}
+		}
+		dominant, ok := dominantField(fields[i : i+advance])
+		if advance > 1 {
+			conflicts = append(conflicts, newConflict(t, fields[i:i+advance], dominant, ok))
+		}
+		if ok {
+			out = append(out, dominant)
+		}
+	}
+
+	// Members tagged "-" are only of interest if they don't hide a field.
+	fields = out
+	for _, f := range ignored {
+		hidden := false
+		for i := range out {
+			if out[i].key() == f.key() {
+				hidden = true
+				break
+			}
+		}
+		if !hidden {
+			fields = append(fields, f)
+		}
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		return lessIndex(fields[i].index, fields[j].index)
+	})
+	return fields, conflicts
+}
+
+// dominantField looks through the fields, all of which are known to have
+// the same name, to find the single field that dominates the others using
+// Go's embedding rules, modified by the presence of JSON tags.  If there
+// are multiple top-level fields, the boolean will be false: This condition
+// is an error in Go and we skip all the fields.
+func dominantField(fields []field) (field, bool) {
+	// The fields are sorted in increasing index-length order, then by
+	// presence of tag.  That means that the first field is the dominant one.
+	// We need only check for error cases: two fields at top level, either
+	// both tagged or neither tagged.
+	if len(fields) > 1 && len(fields[0].index) == len(fields[1].index) &&
+		(len(fields[0].rawtag) > 0) == (len(fields[1].rawtag) > 0) {
+		return field{}, false
+	}
+	return fields[0], true
+}
+
+func lessIndex(x, y []int) bool {
+	for k, xik := range x {
+		if k >= len(y) {
+			return false
+		}
+		if xik != y[k] {
+			return xik < y[k]
+		}
+	}
+	return len(x) < len(y)
+}
+
+// isValidTag reports whether 's' can be used as a JSON name; if not,
+// encoding/json uses the field name.
+func isValidTag(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+			// Backslash and quote chars are reserved, but
+			// otherwise any punctuation chars are allowed
+			// in a tag name.
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			return false
+		}
+	}
+	return true
+}
+
+// fieldByIndex returns the member of the struct 'v' with the index sequence
+// 'index'.  Unlike reflect.Value.FieldByIndex it doesn't panic on a nil
+// embedded struct pointer; the zero value of the struct is used instead.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v = reflect.New(v.Type().Elem())
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// nilEmbedded returns the unexported struct type of a nil embedded pointer
+// in the index sequence 'index' of the struct 'v', if there is one.  As
+// encoding/json can't set such a pointer it fails to decode the member.
+func nilEmbedded(v reflect.Value, index []int) reflect.Type {
+	for _, x := range index[:len(index)-1] {
+		sf := v.Type().Field(x)
+		v = v.Field(x)
+		if v.Kind() != reflect.Ptr {
+			continue
+		}
+		if v.IsNil() {
+			if len(sf.PkgPath) > 0 {
+				return sf.Type.Elem()
+			}
+			v = reflect.New(v.Type().Elem())
+		}
+		v = v.Elem()
+	}
+	return nil
+}
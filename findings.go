@@ -0,0 +1,131 @@
+// findings.go - the results of a check with their positions
+// Copyright © 2016-2019 Charles Banning.  All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package checkjson
+
+import (
+	"fmt"
+)
+
+// A Finding is a result of a check with its position in the JSON object,
+// so that it can be reported as "file:line:col" for editors and CI tools;
+// see Report.Findings.
+type Finding struct {
+	Kind FindingKind
+	Path string   // as listed in the Report - see PathFormat; "" for errors and warnings
+	Pos  Position // of the JSON key or value; for a missing member, of the JSON object it's missing from
+	Err  error    // the Validate error or the warning; nil for the other kinds
+}
+
+func (f Finding) String() string {
+	if f.Err != nil {
+		return fmt.Sprintf("%s: %s: %s", f.Pos, f.Kind, f.Err)
+	}
+	return fmt.Sprintf("%s: %s: %s", f.Pos, f.Kind, f.Path)
+}
+
+// A FindingKind is the list of the Report that a Finding is for.
+type FindingKind int
+
+const (
+	UnknownKey        FindingKind = iota // a JSON key in Report.Unknown
+	MissingMember                        // a struct member in Report.Missing
+	ExistingMember                       // a struct member in Report.Existing
+	MismatchedKey                        // a JSON key in Report.Mismatched
+	CaseMismatchedKey                    // a JSON key in Report.CaseMismatched
+	Failure                              // an error returned by ValidateAll
+	Warning                              // a warning in Report.Warnings
+)
+
+var findingNames = [...]string{"unknown", "missing", "existing", "mismatched", "case mismatched", "error", "warning"}
+
+func (k FindingKind) String() string {
+	if k < 0 || int(k) >= len(findingNames) {
+		return fmt.Sprintf("FindingKind(%d)", int(k))
+	}
+	return findingNames[k]
+}
+
+// find records the Finding 'f'.
+func (w *walker) find(f Finding) {
+	if w.yield == nil {
+		w.r.Findings = append(w.r.Findings, f)
+		return
+	}
+	if w.s.err == nil && !w.yield(f) {
+		w.s.err = errStopped
+	}
+}
+
+// fail records the Validate error 'err' for the JSON value at 'pos', in the
+// context of the JSON value at 'p'.
+func (w *walker) fail(p Path, pos Position, err error) {
+	err = wrap(p, err)
+	if w.r != nil {
+		w.errs = append(w.errs, err)
+	}
+	w.find(Finding{Kind: Failure, Pos: pos, Err: err})
+}
+
+// warn records the warning 'err' for the JSON value at 'pos'.
+func (w *walker) warn(pos Position, err error) {
+	if w.r != nil {
+		w.r.Warnings = append(w.r.Warnings, err)
+	}
+	w.find(Finding{Kind: Warning, Pos: pos, Err: err})
+}
+
+// unknown records the JSON key or value at 'p' that won't be decoded, and
+// the JSON keys that may have been meant for it.
+func (w *walker) unknown(p Path, sugg []string) {
+	s := w.c.format(p, Path.keys)
+	if w.r != nil {
+		w.r.Unknown = append(w.r.Unknown, s)
+		if len(sugg) > 0 {
+			w.r.Suggestions[s] = sugg
+		}
+	}
+	w.find(Finding{Kind: UnknownKey, Path: s, Pos: p.Position()})
+}
+
+// mismatched records the JSON key at 'p' that isn't spelled as the JSON
+// tag 'name'; if 'strict' it won't be decoded, as the case doesn't match.
+func (w *walker) mismatched(p Path, name string, strict bool) {
+	s := w.c.format(p, Path.keysAsIs)
+	k := MismatchedKey
+	if strict {
+		k = CaseMismatchedKey
+	}
+	if w.r != nil {
+		if strict {
+			w.r.CaseMismatched = append(w.r.CaseMismatched, CaseMismatch{s, name})
+		} else {
+			w.r.Mismatched = append(w.r.Mismatched, s)
+		}
+	}
+	w.find(Finding{Kind: k, Path: s, Pos: p.Position()})
+}
+
+// member records the missing or existing member at 'p' in 'm'.  As they
+// are to be listed in struct definition order, they are collected by walk
+// for a Report, and otherwise recorded as they are found.
+func (w *walker) member(m *members, k FindingKind, p Path) {
+	if m.quiet {
+		return
+	}
+	var s string
+	if k == MissingMember {
+		s = w.c.format(p, Path.members)
+	} else {
+		s = w.c.format(p, Path.existing)
+	}
+	f := Finding{Kind: k, Path: s, Pos: p.Position()}
+	if w.r == nil {
+		w.find(f)
+		return
+	}
+	m.findings = append(m.findings, f)
+	m.paths = append(m.paths, p)
+}
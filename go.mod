@@ -0,0 +1,3 @@
+module github.com/clbanning/checkjson
+
+go 1.23
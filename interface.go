@@ -0,0 +1,46 @@
+// interface.go - concrete types for interface members
+// Copyright © 2016-2019 Charles Banning.  All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package checkjson
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var interfaceTypes sync.Map // map[reflect.Type]reflect.Value
+
+// RegisterInterfaceType records the concrete value, 'val', that JSON values
+// for members of interface type 'iface' are checked against.  It is used
+// when an interface member does not hold a non-nil pointer, for which
+// encoding/json would decode into the pointer's value.  E.g.:
+//
+//	checkjson.RegisterInterfaceType(reflect.TypeOf((*AuthConfig)(nil)).Elem(), &BasicAuth{})
+//
+// Without a registered type, the JSON value for an interface member that
+// is nil is not checked.  RegisterInterfaceType panics if 'iface' is not an
+// interface type or if 'val' does not implement it.  A later registration
+// for the same interface type replaces an earlier one.  It is safe to call
+// RegisterInterfaceType while checks are running.
+func RegisterInterfaceType(iface reflect.Type, val interface{}) {
+	if iface == nil || iface.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("checkjson: RegisterInterfaceType of non-interface type %v", iface))
+	}
+	v := reflect.ValueOf(val)
+	if !v.IsValid() || !v.Type().Implements(iface) {
+		panic(fmt.Sprintf("checkjson: RegisterInterfaceType: %T does not implement %v", val, iface))
+	}
+	interfaceTypes.Store(iface, v)
+}
+
+// interfaceValue returns the value registered for the interface type 't'.
+func interfaceValue(t reflect.Type) (reflect.Value, bool) {
+	v, ok := interfaceTypes.Load(t)
+	if !ok {
+		return reflect.Value{}, false
+	}
+	return v.(reflect.Value), true
+}
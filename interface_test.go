@@ -0,0 +1,151 @@
+package checkjson
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type ifAuth interface {
+	Scheme() string
+}
+
+type ifBasic struct {
+	User     string
+	Password string
+}
+
+func (a *ifBasic) Scheme() string { return "basic" }
+
+type ifToken struct {
+	Token string
+}
+
+func (a *ifToken) Scheme() string { return "token" }
+
+type ifConfig struct {
+	Name  string
+	Auth  ifAuth
+	Extra interface{}
+	Chain []ifAuth
+}
+
+func TestInterfaceDynamic(t *testing.T) {
+	fmt.Println("===================== TestInterfaceDynamic ...")
+
+	data := []byte(`{
+		"name":"a",
+		"auth":{"user":"u","pasword":"p"},
+		"extra":{"x":0,"y":0,"z":0},
+		"chain":[{"token":"t"},{"user":"u","tokn":"t"},{"any":1}]
+	}`)
+	cfg := &ifConfig{
+		Auth:  &ifBasic{},
+		Extra: &arrPoint{},
+		Chain: []ifAuth{&ifToken{}, &ifBasic{}},
+	}
+	keys, err := UnknownJSONKeys(data, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// chain.3 has no element to decode into, and ifAuth is not registered
+	want := "[auth.pasword extra.z chain.2.tokn]"
+	if s := fmt.Sprint(keys); s != want {
+		t.Fatal("unknown:", s, "!=", want)
+	}
+
+	err = Validate(data, cfg)
+	var uerr *UnknownKeyError
+	if !errors.As(err, &uerr) || uerr.Path.String() != "auth.pasword" {
+		t.Fatalf("err: %v", err)
+	}
+	fmt.Println("err ok:", err)
+
+	// without dynamic values there is nothing to check against
+	keys, err = UnknownJSONKeys(data, new(ifConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 0 {
+		t.Fatal("unknown:", keys)
+	}
+}
+
+type ifRegistered interface {
+	Kind() string
+}
+
+type ifTLS struct {
+	CertFile string `json:"cert_file"`
+	KeyFile  string `json:"key_file"`
+}
+
+func (t *ifTLS) Kind() string { return "tls" }
+
+type ifACME struct {
+	Email string
+}
+
+func (a *ifACME) Kind() string { return "acme" }
+
+type ifServer struct {
+	Host     string
+	Security ifRegistered
+	Others   []ifRegistered
+	Backup   *ifRegistered
+}
+
+func TestInterfaceRegistered(t *testing.T) {
+	fmt.Println("===================== TestInterfaceRegistered ...")
+
+	RegisterInterfaceType(reflect.TypeOf((*ifRegistered)(nil)).Elem(), &ifTLS{})
+
+	data := []byte(`{
+		"host":"a",
+		"security":{"cert_file":"c","keyfile":"k"},
+		"others":[{"cert_file":"c"},{"key_fil":"k"}],
+		"backup":{"cert_file":"c","key_file":"k","ca":"x"}
+	}`)
+	r, err := Check(data, new(ifServer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "[security.keyfile others.2.key_fil backup.ca]"
+	if s := fmt.Sprint(r.Unknown); s != want {
+		t.Fatal("unknown:", s, "!=", want)
+	}
+	want = "[Security.key_file Others.key_file Others.cert_file Others.key_file]"
+	if s := fmt.Sprint(r.Missing); s != want {
+		t.Fatal("missing:", s, "!=", want)
+	}
+
+	// a dynamic pointer value takes precedence over the registered type
+	srv := &ifServer{Security: &ifACME{}}
+	keys, err := UnknownJSONKeys([]byte(`{"security":{"email":"e","cert_file":"c"}}`), srv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(keys); s != "[security.cert_file]" {
+		t.Fatal("unknown:", s)
+	}
+}
+
+func TestRegisterInterfaceTypePanics(t *testing.T) {
+	fmt.Println("===================== TestRegisterInterfaceTypePanics ...")
+
+	for i, f := range []func(){
+		func() { RegisterInterfaceType(reflect.TypeOf(ifTLS{}), &ifTLS{}) },
+		func() { RegisterInterfaceType(reflect.TypeOf((*ifRegistered)(nil)).Elem(), ifTLS{}) },
+		func() { RegisterInterfaceType(reflect.TypeOf((*ifRegistered)(nil)).Elem(), nil) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("no panic for case", i)
+				}
+			}()
+			f()
+		}()
+	}
+}
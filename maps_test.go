@@ -0,0 +1,135 @@
+package checkjson
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type mapServer struct {
+	Host string
+	Port int
+}
+
+type mapConfig struct {
+	Servers map[string]mapServer   `json:"servers"`
+	Pools   map[string][]mapServer `json:"pools"`
+	Backups map[string]*mapServer  `json:"backups"`
+	Weights map[string]int         `json:"weights"`
+}
+
+func TestMapValues(t *testing.T) {
+	fmt.Println("===================== TestMapValues ...")
+
+	data := []byte(`{
+		"servers":{
+			"web1":{"host":"a","port":80},
+			"Web2":{"host":"b","prot":81}
+		},
+		"pools":{"db":[{"host":"c","port":5432},{"hots":"d"}]},
+		"backups":{"web1":{"host":"e","port":80,"extra":true}},
+		"weights":{"web1":1,"web2":2}
+	}`)
+	r, err := Check(data, new(mapConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "[servers.Web2.prot pools.db.2.hots backups.web1.extra]"
+	if s := fmt.Sprint(r.Unknown); s != want {
+		t.Fatal("unknown:", s, "!=", want)
+	}
+	want = "[servers.Web2.Port pools.db.Host pools.db.Port]"
+	if s := fmt.Sprint(r.Missing); s != want {
+		t.Fatal("missing:", s, "!=", want)
+	}
+
+	err = Validate(data, new(mapConfig))
+	var uerr *UnknownKeyError
+	if !errors.As(err, &uerr) || uerr.Path.String() != "servers.Web2.prot" {
+		t.Fatalf("err: %v", err)
+	}
+	fmt.Println("err ok:", err)
+
+	data = []byte(`{"servers":[{"host":"a"}]}`)
+	err = Validate(data, new(mapConfig))
+	var oerr *NotObjectError
+	if !errors.As(err, &oerr) || oerr.Path.String() != "servers" {
+		t.Fatalf("err: %v", err)
+	}
+	fmt.Println("err ok:", err)
+}
+
+// mapColor is a map key decoded from text.
+type mapColor int
+
+func (c *mapColor) UnmarshalText(b []byte) error {
+	switch strings.ToLower(string(b)) {
+	case "red":
+		*c = 1
+	case "green":
+		*c = 2
+	default:
+		return fmt.Errorf("unknown color: %s", b)
+	}
+	return nil
+}
+
+func TestMapKeys(t *testing.T) {
+	fmt.Println("===================== TestMapKeys ...")
+
+	type test struct {
+		Ports  map[int]mapServer
+		Small  map[int8]bool
+		Counts map[uint]int
+		Colors map[mapColor]string
+		Floats map[float64]string
+		Flags  map[bool]string
+	}
+
+	data := []byte(`{
+		"ports":{"80":{"host":"a"},"http":{"host":"b"}},
+		"small":{"127":true,"128":false},
+		"counts":{"1":1,"-1":2},
+		"colors":{"red":"#f00","Green":"#0f0","blue":"#00f"}
+	}`)
+	if err := decodeStrict(data, new(test)); err == nil {
+		t.Fatal("encoding/json decoded invalid keys")
+	}
+	err := ValidateAll(data, new(test))
+	if err == nil {
+		t.Fatal("no error returned")
+	}
+	var keys []string
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var merr *MapKeyError
+		if !errors.As(e, &merr) {
+			t.Fatalf("not a MapKeyError: %v", e)
+		}
+		keys = append(keys, merr.Path.String())
+	}
+	want := "[ports.http small.128 counts.-1 colors.blue]"
+	if s := fmt.Sprint(keys); s != want {
+		t.Fatal("keys:", s, "!=", want)
+	}
+	fmt.Println("err ok:", err)
+
+	data = []byte(`{"ports":{"80":{"host":"a"}},"small":{"-128":true},"colors":{"RED":""}}`)
+	if err := decodeStrict(data, new(test)); err != nil {
+		t.Fatal("encoding/json:", err)
+	}
+	if err := Validate(data, new(test)); err != nil {
+		t.Fatal(err)
+	}
+
+	data = []byte(`{"floats":{"1.5":"a"},"flags":{"true":"a"}}`)
+	if err := decodeStrict(data, new(test)); err == nil {
+		t.Fatal("encoding/json decoded bool keys")
+	}
+	err = Validate(data, new(test))
+	var merr *MapKeyError
+	if !errors.As(err, &merr) || merr.Path.String() != "flags.true" {
+		t.Fatalf("err: %v", err)
+	}
+	fmt.Println("err ok:", err)
+}
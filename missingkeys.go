@@ -5,38 +5,23 @@
 
 package checkjson
 
-import (
-	"encoding/json"
-	"reflect"
-	"strings"
-)
-
-type skipmems struct {
-	val   string
-	depth int
-}
-
-// Slice of dot-notation struct fields that can be missing in JSON object.
-var skipmembers = []skipmems{}
-
 // SetMembersToIgnore creates a list of exported struct field names that should not be checked
 // for as keys in the JSON object.  For hierarchical struct members provide the full path for
 // the member name using dot-notation. Calling SetMembersToIgnore with no arguments -
 // SetMembersToIgnore() - clears the list.
+//
+// The member paths are patterns, as for SetKeysToIgnore: "*" matches any one
+// member, map key or array element, "**" any number of them, and "[]" any
+// array element; e.g., "plugins.*.timeout" or "**.comment".  Unlike keys,
+// a member name without dot-notation is only matched at the top level.
+//
+// SetMembersToIgnore configures the default Checker used by the package level
+// functions.  Use NewChecker with the MembersToIgnore option for a Checker with
+// its own list of members.
 func SetMembersToIgnore(s ...string) {
-	if len(s) == 0 {
-		skipmembers = skipmembers[:0]
-		return
-	}
-	skipmembers = make([]skipmems, len(s))
-	for i, v := range s {
-		skipmembers[i] = skipmems{strings.ToLower(v), len(strings.Split(v, "."))}
-	}
+	MembersToIgnore(s...)(std)
 }
 
-// Should we ignore "omitempty" struct tags. By default accept tag.
-var omitemptyOK = true
-
 // IgnoreOmitemptyTag determines whether a `json:",omitempty"` tag is recognized or
 // not with respect to the JSON object.  By default MissingJSONKeys will not include
 // struct fields that are tagged with "omitempty" in the list of missing JSON keys.
@@ -46,12 +31,30 @@ var omitemptyOK = true
 // Calling IgnoreOmitemptyTag with no arguments toggles the handling on/off.  If
 // the alternative argument is passed, then the argument value determines the
 // "omitempty" handling behavior.
+//
+// IgnoreOmitemptyTag configures the default Checker used by the package level
+// functions.  Use NewChecker with the OmitemptyTag option for a Checker with
+// its own "omitempty" handling.
 func IgnoreOmitemptyTag(ok ...bool) {
 	if len(ok) == 0 {
-		omitemptyOK = !omitemptyOK
+		std.omitemptyOK = !std.omitemptyOK
 		return
 	}
-	omitemptyOK = ok[0]
+	std.omitemptyOK = ok[0]
+}
+
+// SetNullsAsMissing determines whether a JSON key with the value null is
+// treated as if it were not in the JSON object.  By default such a key is
+// counted as present, so its struct field is listed by ExistingJSONKeys,
+// although encoding/json only sets pointer, slice, map and interface fields
+// to nil for null and leaves other fields unchanged.  With 'true' the field
+// is listed by MissingJSONKeys instead - unless it's tagged "omitempty".
+//
+// SetNullsAsMissing configures the default Checker used by the package level
+// functions.  Use NewChecker with the NullsAsMissing option for a Checker with
+// its own null handling.
+func SetNullsAsMissing(ok bool) {
+	std.nullsmissing = ok
 }
 
 // MissingJSONKeys returns a list of fields of a struct that will NOT be set
@@ -70,174 +73,23 @@ func IgnoreOmitemptyTag(ok ...bool) {
 // tags - this might be useful if you want to find the "omitempty" fields that
 // are not set by decoding the JSON object.
 //
-// If the struct has a member struct with `checkjson:"norecurse"` tag, then it is not scanned.
+// Struct fields with the `checkjson:"required"` tag are always included when
+// they are missing, whatever their "omitempty" attribute; struct fields with
+// the `checkjson:"optional"` tag never are.  See RequireKeys.
+//
+// If the struct has a member struct with `checkjson:"norecurse"` tag, then it is not scanned:
+// its members are neither missing nor existing.  (UnknownJSONKeys and Validate still
+// check the JSON value of the member.)
 func MissingJSONKeys(b []byte, val interface{}) ([]string, error) {
-	s := make([]string, 0)
-	m := make(map[string]interface{})
-	if err := json.Unmarshal(b, &m); err != nil {
-		return s, ResolveJSONError(b, err)
-	}
-	checkMembers(m, reflect.ValueOf(val), &s, "")
-	return s, nil
+	return std.MissingJSONKeys(b, val)
 }
 
-// cmem is the parent struct member for nested structs
-// If the struct has a member struct with `checkjson:"norecurse"` tag, then it is not scanned.
-func checkMembers(mv interface{}, val reflect.Value, s *[]string, cmem string) {
-	// 1. Convert any pointer value.
-	if val.Kind() == reflect.Ptr {
-		val = reflect.Indirect(val) // convert ptr to struc
-	}
-	// zero Value?
-	if !val.IsValid() {
-		return
-	}
-	typ := val.Type()
-
-	// json.RawMessage is a []byte/[]uint8 and has Kind() == reflect.Slice
-	if typ.Name() == "RawMessage" {
-		return
-	}
-
-	// 2. If its a slice then 'mv' should hold a []interface{} value.
-	//    Loop through the members of 'mv' and see that they are valid relative
-	//    to the <T> of val []<T>.
-	if typ.Kind() == reflect.Slice {
-		tval := typ.Elem()
-		if tval.Kind() == reflect.Ptr {
-			tval = tval.Elem()
-		}
-		// slice may be nil, so create a Value of it's type
-		// 'mv' must be of type []interface{}
-		sval := reflect.New(tval)
-		slice, ok := mv.([]interface{})
-		if !ok {
-			// encodiong/json must have a JSON array value to decode
-			// unlike encoding/xml which will decode a list of elements
-			// to a singleton or vise-versa.
-			*s = append(*s, typ.Name())
-			return
-		}
-		// 2.1. Check members of JSON array.
-		//      This forces all of them to be regular and w/o typos in key labels.
-		for _, sl := range slice {
-			// cmem is the member name for the slice - []<T> - value
-			checkMembers(sl, sval, s, cmem)
-		}
-		return // done with reflect.Slice value
-	}
-
-	// 3a. Ignore anything that's not a struct.
-	if typ.Kind() != reflect.Struct {
-		return // just ignore it - don't look for k:v pairs
-	}
-	// 3b. map value must represent k:v pairs
-	mm, ok := mv.(map[string]interface{})
-	if !ok {
-		*s = append(*s, typ.Name())
-	}
-	// 3c. Coerce keys to lower case.
-	mkeys := make(map[string]interface{}, len(mm))
-	for k, v := range mm {
-		mkeys[strings.ToLower(k)] = v
-	}
-
-	// 4. Build the list of struct field name:value
-	//    We make every key (field) label look like an exported label - "Fieldname".
-	//    If there is a JSON tag it is used instead of the field label, and saved to
-	//    insure that the spec'd tag matches the JSON key exactly.
-	type fieldSpec struct {
-		name      string
-		val       reflect.Value
-		tag       string
-		omitempty bool
-		norecurse bool
-	}
-	fields := make([]*fieldSpec, 0) // use a list so members are in sequence
-	var tag string
-	var oempty bool
-	var norecurse bool
-	for i := 0; i < val.NumField(); i++ {
-		tag = ""
-		oempty = false
-		norecurse = false
-		if len(typ.Field(i).PkgPath) > 0 {
-			continue // field is NOT exported
-		}
-		t := typ.Field(i).Tag.Get("json")
-		tags := strings.Split(t, ",")
-		tag = strings.ToLower(tags[0])
-		// handle ignore member JSON tag, "-"
-		if tag == "-" {
-			continue
-		}
-		// scan rest of tags for "omitempty"
-		for _, v := range tags[1:] {
-			if v == "omitempty" {
-				oempty = true
-				break
-			}
-		}
-		t = typ.Field(i).Tag.Get("checkjson")
-		if t == "norecurse" {
-			norecurse = true
-			break
-		}
-		fields = append(fields, &fieldSpec{typ.Field(i).Name, val.Field(i), tag, oempty, norecurse})
-	}
-
-	// 5. check that field names/tags have corresponding map key
-	// var ok bool
-	var v interface{}
-	// var err error
-	cmemdepth := 1
-	if len(cmem) > 0 {
-		cmemdepth = len(strings.Split(cmem, ".")) + 1 // struct hierarchy
-	}
-	lcmem := strings.ToLower(cmem)
-	name := ""
-	for _, field := range fields {
-		lm := strings.ToLower(field.name)
-		for _, sm := range skipmembers {
-			// skip any skipmembers values that aren't at same depth
-			if cmemdepth != sm.depth {
-				continue
-			}
-			if len(cmem) > 0 {
-				if lcmem+`.`+lm == sm.val {
-					goto next
-				}
-			} else if lm == sm.val {
-				goto next
-			}
-		}
-		if len(field.tag) > 0 {
-			name = field.tag
-			v, ok = mkeys[field.tag]
-		} else {
-			name = field.name
-			v, ok = mkeys[lm]
-		}
-		// If map key is missing, then record it
-		// if there's no omitempty tag or we're ignoring  omitempty tag.
-		if !ok && (!field.omitempty || !omitemptyOK) {
-			if len(cmem) > 0 {
-				// *s = append(*s, cmem+`.`+field.name)
-				*s = append(*s, cmem+`.`+name)
-			} else {
-				// *s = append(*s, field.name)
-				*s = append(*s, name)
-			}
-			goto next // don't drill down further; no key in JSON object
-		}
-		if field.norecurse {
-			goto next // don't drill down further
-		}
-		if len(cmem) > 0 {
-			checkMembers(v, field.val, s, cmem+`.`+name)
-		} else {
-			checkMembers(v, field.val, s, name)
-		}
-	next:
+// MissingJSONKeys is like the package level MissingJSONKeys function but
+// uses the Checker's settings.
+func (c *Checker) MissingJSONKeys(b []byte, val interface{}) ([]string, error) {
+	r, err := c.Check(b, val)
+	if err != nil {
+		return make([]string, 0), err
 	}
+	return r.Missing, nil
 }
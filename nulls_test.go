@@ -0,0 +1,155 @@
+package checkjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type nlConfig struct {
+	Port   int
+	Name   *string
+	Tags   []string
+	Opts   map[string]int
+	Any    interface{}
+	When   time.Time
+	Inner  struct{ X int }
+	Counts map[string]int
+	List   []int
+	Quoted int `json:",string"`
+	Maybe  int `json:",omitempty"`
+}
+
+var nlData = []byte(`{
+	"port":null,
+	"name":null,
+	"tags":null,
+	"opts":null,
+	"any":null,
+	"when":null,
+	"inner":null,
+	"counts":{"a":null},
+	"list":[1,null],
+	"quoted":null,
+	"maybe":null
+}`)
+
+func TestNullWarnings(t *testing.T) {
+	fmt.Println("===================== TestNullWarnings ...")
+
+	if err := decodeStrict(nlData, new(nlConfig)); err != nil {
+		t.Fatal("encoding/json:", err)
+	}
+	if err := ValidateAll(nlData, new(nlConfig)); err != nil {
+		t.Fatal(err)
+	}
+	r, err := Check(nlData, new(nlConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, w := range r.Warnings {
+		nw, ok := w.(*NullWarning)
+		if !ok {
+			t.Fatalf("not a NullWarning: %v", w)
+		}
+		got = append(got, nw.Path.String())
+	}
+	// null is passed to time.Time's UnmarshalJSON, so "when" isn't listed
+	want := "[port inner list.2 quoted maybe]"
+	if s := fmt.Sprint(got); s != want {
+		t.Fatal("warnings:", s, "!=", want)
+	}
+	fmt.Println(r.Warnings[0])
+
+	// by default null is a value
+	if len(r.Missing) != 0 {
+		t.Fatal("missing:", r.Missing)
+	}
+}
+
+// nlNullString is like sql.NullString: null sets Valid to false.
+type nlNullString struct {
+	String string
+	Valid  bool
+}
+
+func (n *nlNullString) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		n.Valid = false
+		return nil
+	}
+	n.Valid = true
+	return json.Unmarshal(b, &n.String)
+}
+
+// nlText only implements encoding.TextUnmarshaler, which null isn't passed to.
+type nlText struct{ V string }
+
+func (t *nlText) UnmarshalText(b []byte) error {
+	t.V = string(b)
+	return nil
+}
+
+func TestNullUnmarshaler(t *testing.T) {
+	fmt.Println("===================== TestNullUnmarshaler ...")
+
+	type config struct {
+		NS nlNullString
+		TX nlText
+	}
+	data := []byte(`{"ns":null,"tx":null}`)
+	v := config{nlNullString{"x", true}, nlText{"x"}}
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.NS.Valid || v.TX.V != "x" {
+		t.Fatalf("encoding/json: %+v", v)
+	}
+
+	r, err := Check(data, new(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, w := range r.Warnings {
+		got = append(got, w.(*NullWarning).Path.String())
+	}
+	if s := fmt.Sprint(got); s != "[tx]" {
+		t.Fatal("warnings:", s)
+	}
+}
+
+func TestNullsAsMissing(t *testing.T) {
+	fmt.Println("===================== TestNullsAsMissing ...")
+
+	c := NewChecker(NullsAsMissing(true))
+	mems, err := c.MissingJSONKeys(nlData, new(nlConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "[Port Name Tags Opts Any When Inner Quoted]"
+	if s := fmt.Sprint(mems); s != want {
+		t.Fatal("missing:", s, "!=", want)
+	}
+	mems, err = c.ExistingJSONKeys(nlData, new(nlConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Maybe is tagged "omitempty", so it's treated as existing - as if absent
+	want = "[Counts List Maybe]"
+	if s := fmt.Sprint(mems); s != want {
+		t.Fatal("existing:", s, "!=", want)
+	}
+
+	SetNullsAsMissing(true)
+	defer SetNullsAsMissing(false)
+	mems, err = MissingJSONKeys([]byte(`{"port":null,"name":"a"}`), new(nlConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(mems); s != "[Port Tags Opts Any When Inner Counts List Quoted]" {
+		t.Fatal("missing:", s)
+	}
+}
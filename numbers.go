@@ -0,0 +1,124 @@
+// numbers.go - check JSON numbers against numeric member types
+// Copyright © 2016-2019 Charles Banning.  All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package checkjson
+
+import (
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// checkNumber checks that the JSON number 'n' can be decoded to the numeric
+// type 't': that it's an integer for an integer type, and that it's in range
+// for the size and sign of the type.  The number is parsed from its literal
+// in the JSON object, so no precision is lost by the check.
+func (w *walker) checkNumber(n *node, t reflect.Type, p Path) {
+	var err error
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !isInteger(n.text) {
+			err = ErrNotInteger
+		} else if _, perr := strconv.ParseInt(n.text, 10, t.Bits()); perr != nil {
+			err = ErrOutOfRange
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if !isInteger(n.text) {
+			err = ErrNotInteger
+		} else if strings.HasPrefix(n.text, "-") {
+			err = ErrNegative
+		} else if _, perr := strconv.ParseUint(n.text, 10, t.Bits()); perr != nil {
+			err = ErrOutOfRange
+		}
+	case reflect.Float32, reflect.Float64:
+		if _, perr := strconv.ParseFloat(n.text, t.Bits()); perr != nil {
+			err = ErrOutOfRange
+		}
+	default:
+		return // json.Number
+	}
+	if err != nil {
+		w.fail(p, p.Position(), &NumberError{p, t, n.text, err})
+		return
+	}
+	if t.Bits() == 64 && t.Kind() != reflect.Float64 && beyondFloat64(n.text) {
+		w.warn(p.Position(), &PrecisionWarning{p, t, n.text, w.c.pathstyle})
+	}
+}
+
+// checkFloat64 warns of the integers in the JSON value 'n' that lose
+// precision when encoding/json decodes them to float64 values for the
+// empty interface type 't'.
+func (w *walker) checkFloat64(n *node, t reflect.Type, p Path) {
+	switch n.kind {
+	case numberKind:
+		if isInteger(n.text) && beyondFloat64(n.text) {
+			w.warn(p.Position(), &PrecisionWarning{p, t, n.text, w.c.pathstyle})
+		}
+	case arrayKind:
+		for i := 0; w.s.more(n); i++ {
+			e := w.s.value()
+			w.checkFloat64(e, t, p.elem(i).at(e.pos))
+		}
+	case objectKind:
+		for w.s.more(n) {
+			k, pos := w.s.key()
+			w.checkFloat64(w.s.value(), t, p.entry(k).at(pos))
+		}
+	}
+}
+
+// isInteger reports whether the JSON number 's' is written as an integer;
+// as with encoding/json, 1.0 and 1e3 aren't integers.
+func isInteger(s string) bool {
+	return !strings.ContainsAny(s, ".eE")
+}
+
+// isNumber reports whether 's' is a JSON number literal; encoding/json
+// only decodes a JSON string to a json.Number if it holds one.
+func isNumber(s string) bool {
+	const digits = "0123456789"
+	s = strings.TrimPrefix(s, "-")
+	if len(s) == 0 || !strings.ContainsRune(digits, rune(s[0])) {
+		return false
+	}
+	if s[0] == '0' {
+		s = s[1:]
+	} else {
+		s = strings.TrimLeft(s, digits)
+	}
+	if len(s) > 0 && s[0] == '.' {
+		if len(s) < 2 || !strings.ContainsRune(digits, rune(s[1])) {
+			return false
+		}
+		s = strings.TrimLeft(s[1:], digits)
+	}
+	if len(s) > 0 && (s[0] == 'e' || s[0] == 'E') {
+		s = s[1:]
+		if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+			s = s[1:]
+		}
+		if len(s) == 0 || !strings.ContainsRune(digits, rune(s[0])) {
+			return false
+		}
+		s = strings.TrimLeft(s, digits)
+	}
+	return len(s) == 0
+}
+
+// maxExact is the largest magnitude of the integers that a float64 holds
+// exactly: 2^53.
+var maxExact = new(big.Int).Lsh(big.NewInt(1), 53)
+
+// beyondFloat64 reports whether the JSON integer 's' has a magnitude greater
+// than 2^53.
+func beyondFloat64(s string) bool {
+	if len(s) < 16 {
+		return false // 2^53 has 16 digits
+	}
+	i, ok := new(big.Int).SetString(s, 10)
+	return ok && i.CmpAbs(maxExact) > 0
+}
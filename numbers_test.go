@@ -0,0 +1,103 @@
+package checkjson
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type nuLimits struct {
+	Small  uint8
+	Count  uint
+	Level  int8
+	Total  int
+	Big    int64
+	Huge   uint64
+	Ratio  float32
+	Scale  float64
+	Extra  interface{}
+	Values []int16
+}
+
+func TestNumbers(t *testing.T) {
+	fmt.Println("===================== TestNumbers ...")
+
+	tests := []string{
+		`{"small":255,"count":0,"level":-128,"total":-1,"big":9223372036854775807,"huge":18446744073709551615}`,
+		`{"ratio":3.4e38,"scale":1.7e308,"values":[32767,-32768]}`,
+		`{"small":256}`,
+		`{"small":-1}`,
+		`{"count":-1}`,
+		`{"count":-0}`,
+		`{"level":128}`,
+		`{"total":1.5}`,
+		`{"total":1.0}`,
+		`{"total":1e3}`,
+		`{"total":-0}`,
+		`{"big":9223372036854775808}`,
+		`{"huge":18446744073709551616}`,
+		`{"ratio":3.5e38}`,
+		`{"ratio":1e-50}`,
+		`{"scale":1e309}`,
+		`{"values":[1,32768]}`,
+	}
+	for i, test := range tests {
+		data := []byte(test)
+		jerr := decodeStrict(data, new(nuLimits))
+		verr := Validate(data, new(nuLimits))
+		if (jerr == nil) != (verr == nil) {
+			t.Fatalf("#%d %s:\nencoding/json: %v\ncheckjson: %v", i, data, jerr, verr)
+		}
+	}
+
+	data := []byte(`{"small":300,"count":-1,"total":1.5,"values":[1,2,99999]}`)
+	err := ValidateAll(data, new(nuLimits))
+	var got []string
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var nerr *NumberError
+		if !errors.As(e, &nerr) {
+			t.Fatalf("not a NumberError: %v", e)
+		}
+		got = append(got, fmt.Sprintf("%s:%s:%v", nerr.Path, nerr.Value, nerr.Err))
+	}
+	want := "[small:300:out of range count:-1:negative total:1.5:not an integer values.3:99999:out of range]"
+	if s := fmt.Sprint(got); s != want {
+		t.Fatal("errors:", s, "!=", want)
+	}
+	if !errors.Is(err, ErrNegative) {
+		t.Fatal("not ErrNegative:", err)
+	}
+	fmt.Println("err ok:", err)
+}
+
+func TestPrecisionWarning(t *testing.T) {
+	fmt.Println("===================== TestPrecisionWarning ...")
+
+	data := []byte(`{
+		"big":9007199254740993,
+		"huge":9007199254740992,
+		"total":-9007199254740993,
+		"scale":9007199254740993,
+		"extra":{"id":12345678901234567890,"ids":[1,-9007199254740993],"f":1e300}
+	}`)
+	if err := Validate(data, new(nuLimits)); err != nil {
+		t.Fatal(err)
+	}
+	r, err := Check(data, new(nuLimits))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, w := range r.Warnings {
+		pw, ok := w.(*PrecisionWarning)
+		if !ok {
+			t.Fatalf("not a PrecisionWarning: %v", w)
+		}
+		got = append(got, fmt.Sprintf("%s:%s", pw.Path, pw.Type))
+		fmt.Println(pw)
+	}
+	want := "[big:int64 total:int extra.id:interface {} extra.ids.2:interface {}]"
+	if s := fmt.Sprint(got); s != want {
+		t.Fatal("warnings:", s, "!=", want)
+	}
+}
@@ -0,0 +1,125 @@
+package checkjson
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// The parity tests check that Validate accepts a JSON object if and only if
+// encoding/json decodes it with DisallowUnknownFields.
+
+type parPlain struct {
+	Name     string
+	UserID   int
+	HTTPPort int
+}
+
+type parTagged struct {
+	Scheme   string `json:"s"`
+	Kelvin   string `json:"k"`
+	MyName   string `json:"my-name"`
+	Summer   string `json:"été"`
+	Sigma    string `json:"σ"`
+	Multiple string `json:"first second"`
+}
+
+type parInner1 struct{ Y int }
+type parInner2 struct{ X int }
+
+// parExact has two members whose names differ only in case.
+type parExact struct {
+	Lower parInner1 `json:"name"`
+	Upper parInner2 `json:"Name"`
+}
+
+type parEmbedded struct {
+	embBase
+	Name parInner2
+}
+
+func TestParity(t *testing.T) {
+	fmt.Println("===================== TestParity ...")
+
+	tests := []struct {
+		val  interface{}
+		data string
+	}{
+		{parPlain{}, `{"name":"a","NAME":"b","nAmE":"c"}`},
+		{parPlain{}, `{"userid":1,"userId":2,"USERID":3,"httpport":4}`},
+		{parPlain{}, `{"user_id":1}`},
+		{parPlain{}, `{"http port":1}`},
+		{parPlain{}, `{"İd":1}`},            // 'İ' lower cases to "i", but doesn't fold
+		{parPlain{}, "{\"na\u200bme\":1}"},  // zero width space
+		{parPlain{}, `{"nam\u0130":"a"}`},   // 'İ'
+		{parPlain{}, "{\"user\u0131d\":1}"}, // dotless 'ı'
+		{parTagged{}, `{"s":"a","S":"b"}`},
+		{parTagged{}, `{"ſ":"a"}`},          // long s
+		{parTagged{}, "{\"\u212a\":\"a\"}"}, // Kelvin sign
+		{parTagged{}, `{"K":"a"}`},
+		{parTagged{}, `{"my-name":"a","MY-NAME":"b"}`},
+		{parTagged{}, `{"my_name":"a"}`},
+		{parTagged{}, `{"myname":"a"}`},
+		{parTagged{}, `{"ÉTÉ":"a","Été":"b"}`},
+		{parTagged{}, `{"été ":"a"}`},
+		{parTagged{}, `{"Σ":"a","ς":"b"}`}, // final sigma folds with sigma
+		{parTagged{}, `{"FIRST SECOND":"a"}`},
+		{parTagged{}, `{"First Second":"a"}`},
+		{parTagged{}, `{"firstsecond":"a"}`},
+		{parTagged{}, `{"multiple":"a"}`},
+		{parExact{}, `{"name":{"y":1},"Name":{"x":1}}`},
+		{parExact{}, `{"NAME":{"y":1}}`}, // first folded match wins
+		{parExact{}, `{"NAME":{"x":1}}`},
+		{parExact{}, `{"nAme":{"y":1}}`},
+		{parExact{}, `{"Name":{"y":1}}`},
+		{parEmbedded{}, `{"id":"a","name":{"x":1}}`},
+		{parEmbedded{}, `{"Name":{"x":1}}`},
+		{parEmbedded{}, `{"name":"a"}`},
+		{embHidden{}, `{"z":1}`},
+		{embHidden{}, `{"id":"a"}`}, // nil embedded pointer to an unexported struct
+		{embHidden{}, `{"id":null}`},
+	}
+
+	for i, test := range tests {
+		data := []byte(test.data)
+		typ := reflect.TypeOf(test.val)
+		jerr := decodeStrict(data, reflect.New(typ).Interface())
+		verr := Validate(data, reflect.New(typ).Interface())
+		if (jerr == nil) != (verr == nil) {
+			t.Fatalf("#%d %s %s:\nencoding/json: %v\ncheckjson: %v", i, typ.Name(), data, jerr, verr)
+		}
+		keys, err := UnknownJSONKeys(data, reflect.New(typ).Interface())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (jerr == nil) != (len(keys) == 0) {
+			t.Fatalf("#%d %s %s:\nencoding/json: %v\nunknown keys: %v", i, typ.Name(), data, jerr, keys)
+		}
+	}
+}
+
+func TestFoldName(t *testing.T) {
+	fmt.Println("===================== TestFoldName ...")
+
+	for _, s := range [][2]string{
+		{"name", "NAME"},
+		{"s", "ſ"},
+		{"k", "\u212a"},
+		{"été", "ÉTÉ"},
+		{"σ", "ς"},
+		{"ǆ", "ǅ"},
+	} {
+		if foldName(s[0]) != foldName(s[1]) {
+			t.Fatalf("%q and %q don't fold the same", s[0], s[1])
+		}
+	}
+	for _, s := range [][2]string{
+		{"i", "İ"},
+		{"i", "ı"},
+		{"my_name", "my-name"},
+	} {
+		if foldName(s[0]) == foldName(s[1]) {
+			t.Fatalf("%q and %q fold the same", s[0], s[1])
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// parse.go - decode a JSON object retaining key order
+// Copyright © 2016-2019 Charles Banning.  All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package checkjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"unicode/utf8"
+)
+
+// jsonKind is the kind of a JSON value.
+type jsonKind int
+
+const (
+	nullKind jsonKind = iota
+	boolKind
+	numberKind
+	stringKind
+	arrayKind
+	objectKind
+)
+
+var kindNames = [...]string{"null", "bool", "number", "string", "array", "object"}
+
+func (k jsonKind) String() string {
+	return kindNames[k]
+}
+
+// node is a JSON value read by a scanner.  An array or object node is
+// returned open, and its elements are read as they are checked; only fill
+// sets its keys and elems.  Unlike the map[string]interface{} that
+// json.Unmarshal produces, an object node keeps its keys in document order,
+// so results can be reported in a stable order.
+type node struct {
+	kind  jsonKind
+	text  string   // string value, number literal, or "true"/"false"
+	keys  []string // object keys, in document order
+	elems []*node  // object values - parallel to keys - or array elements
+	pos   Position // of the value in the JSON object
+	kpos  []Position
+	open  bool // the elements are still to be read - see scanner
+}
+
+// value returns the JSON text for a scalar node and an abbreviation for
+// an array or object, for use in error messages.
+func (n *node) value() string {
+	switch n.kind {
+	case nullKind:
+		return "null"
+	case stringKind:
+		s := n.text
+		if len(s) > 40 {
+			i := 37
+			for i > 0 && !utf8.RuneStart(s[i]) {
+				i--
+			}
+			s = s[:i] + "..."
+		}
+		return strconv.Quote(s)
+	case arrayKind:
+		return "[...]"
+	case objectKind:
+		return "{...}"
+	}
+	return n.text
+}
+
+// appendJSON appends the JSON encoding of 'n' to 'b'.  Number literals are
+// kept as they are written, so no precision is lost.
+func (n *node) appendJSON(b []byte) []byte {
+	switch n.kind {
+	case nullKind:
+		return append(b, "null"...)
+	case stringKind:
+		s, _ := json.Marshal(n.text)
+		return append(b, s...)
+	case arrayKind:
+		b = append(b, '[')
+		for i, e := range n.elems {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = e.appendJSON(b)
+		}
+		return append(b, ']')
+	case objectKind:
+		b = append(b, '{')
+		for i, k := range n.keys {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			s, _ := json.Marshal(k)
+			b = append(b, s...)
+			b = append(b, ':')
+			b = n.elems[i].appendJSON(b)
+		}
+		return append(b, '}')
+	}
+	return append(b, n.text...)
+}
+
+// parseObject decodes 'b', which must hold a single JSON object or null.
+// Errors are those of a scanner, so they can be passed to ResolveJSONError.
+func parseObject(b []byte) (*node, error) {
+	s := newScanner(bytes.NewReader(b))
+	n := s.top()
+	s.fill(n)
+	s.end()
+	if s.err != nil {
+		return nil, s.err
+	}
+	return n, nil
+}
@@ -0,0 +1,286 @@
+// path.go - locate values in a JSON object
+// Copyright © 2016-2019 Charles Banning.  All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package checkjson
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// A Path locates a value in a JSON object.  It is the sequence of object
+// keys and array element indexes that lead from the top-level object to
+// the value.
+type Path []Segment
+
+// A Segment is a step in a Path: an object key, or an array element if
+// Index is not negative.
+type Segment struct {
+	Key   string   // JSON object key, as it is in the JSON object
+	Index int      // array element index, from 0; -1 for an object key
+	Pos   Position // of the JSON key, or of the array element's value
+	field *field   // struct member for the key, if any
+	entry bool     // the key is for a map entry
+}
+
+// Position returns the position in the JSON object of the last key or
+// array element of the path.  For a struct member that is missing from the
+// JSON object, it is the position of the JSON object it is missing from.
+func (p Path) Position() Position {
+	if len(p) == 0 {
+		return Position{}
+	}
+	return p[len(p)-1].Pos
+}
+
+// A Position is a location in a JSON object.  It is valid if Line > 0.
+type Position struct {
+	Offset int // byte offset, from 0
+	Line   int // line number, from 1
+	Column int // column number, from 1 - in bytes, as for go/token
+}
+
+func (pos Position) String() string {
+	if pos.Line == 0 {
+		return "-"
+	}
+	return strconv.Itoa(pos.Line) + ":" + strconv.Itoa(pos.Column)
+}
+
+// lines locates byte offsets in a JSON object; it is the offsets of the
+// line starts after the first line.
+type lines []int
+
+func newLines(b []byte) lines {
+	var l lines
+	for i, c := range b {
+		if c == '\n' {
+			l = append(l, i+1)
+		}
+	}
+	return l
+}
+
+// position returns the Position for the byte offset 'off'.
+func (l lines) position(off int) Position {
+	i := sort.SearchInts(l, off+1) // lines that start at or before 'off'
+	start := 0
+	if i > 0 {
+		start = l[i-1]
+	}
+	return Position{off, i + 1, off - start + 1}
+}
+
+func (p Path) child(key string, f *field) Path {
+	return append(p[:len(p):len(p)], Segment{Key: key, Index: -1, field: f})
+}
+
+func (p Path) entry(key string) Path {
+	return append(p[:len(p):len(p)], Segment{Key: key, Index: -1, entry: true})
+}
+
+func (p Path) elem(i int) Path {
+	return append(p[:len(p):len(p)], Segment{Index: i})
+}
+
+// at sets the position of the last segment of the new path 'p'.
+func (p Path) at(pos Position) Path {
+	p[len(p)-1].Pos = pos
+	return p
+}
+
+// String returns the path in dot-notation, with array elements numbered
+// from 1; e.g., "vmons.2.keyfilter".
+func (p Path) String() string {
+	s := make([]string, len(p))
+	for i, seg := range p {
+		if seg.Index >= 0 {
+			s[i] = strconv.Itoa(seg.Index + 1)
+		} else {
+			s[i] = seg.Key
+		}
+	}
+	return strings.Join(s, ".")
+}
+
+// Pointer returns the path as an RFC 6901 JSON Pointer, with array elements
+// numbered from 0; e.g., "/vmons/1/keyfilter".
+func (p Path) Pointer() string {
+	var b strings.Builder
+	for _, seg := range p {
+		b.WriteByte('/')
+		if seg.Index >= 0 {
+			b.WriteString(strconv.Itoa(seg.Index))
+			continue
+		}
+		b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(seg.Key))
+	}
+	return b.String()
+}
+
+// JSONPath returns the path as a JSONPath expression, with array elements
+// numbered from 0; e.g., "$.vmons[1].keyfilter".  Keys that aren't
+// identifiers are written in bracket notation; e.g., "$['key.with.dots']".
+func (p Path) JSONPath() string {
+	b := []byte{'$'}
+	for _, seg := range p {
+		switch {
+		case seg.Index >= 0:
+			b = append(b, '[')
+			b = strconv.AppendInt(b, int64(seg.Index), 10)
+			b = append(b, ']')
+		case isIdentifier(seg.Key):
+			b = append(b, '.')
+			b = append(b, seg.Key...)
+		default:
+			b = append(b, "['"...)
+			b = append(b, strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(seg.Key)...)
+			b = append(b, "']"...)
+		}
+	}
+	return string(b)
+}
+
+// GoPath returns the path as a Go expression for the struct member, with
+// array elements numbered from 0 and map keys quoted; e.g.,
+// "VMons[1].KeyFilter" or "Hooks[\"pre\"].Name".  Keys that don't
+// correspond to a struct member are as they are in the JSON object.
+func (p Path) GoPath() string {
+	var b []byte
+	for _, seg := range p {
+		switch {
+		case seg.Index >= 0:
+			b = append(b, '[')
+			b = strconv.AppendInt(b, int64(seg.Index), 10)
+			b = append(b, ']')
+		case seg.entry:
+			b = append(b, '[')
+			b = strconv.AppendQuote(b, seg.Key)
+			b = append(b, ']')
+		default:
+			if len(b) > 0 {
+				b = append(b, '.')
+			}
+			if seg.field != nil {
+				b = append(b, seg.field.name...)
+			} else {
+				b = append(b, seg.Key...)
+			}
+		}
+	}
+	return string(b)
+}
+
+// isIdentifier reports whether 's' can be written in the JSONPath dot
+// notation.
+func isIdentifier(s string) bool {
+	for i, r := range s {
+		if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return len(s) > 0
+}
+
+// A PathStyle is the format of the paths of JSON keys and struct members
+// that are listed by the checks - see PathFormat.
+type PathStyle int
+
+const (
+	// DotNotation lists paths in the dot-notation of the original API,
+	// which differs for each list - see UnknownJSONKeys, MissingJSONKeys
+	// and ExistingJSONKeys.
+	DotNotation PathStyle = iota
+	// JSONPointer lists paths as RFC 6901 JSON Pointers - see Path.Pointer.
+	JSONPointer
+	// JSONPath lists paths as JSONPath expressions - see Path.JSONPath.
+	JSONPath
+	// GoFieldPath lists paths as Go expressions - see Path.GoPath.
+	GoFieldPath
+)
+
+// SetPathFormat sets the format of the paths listed by the package level
+// functions; the default is DotNotation.  Use NewChecker with the PathFormat
+// option for a Checker with its own format.
+func SetPathFormat(s PathStyle) {
+	PathFormat(s)(std)
+}
+
+// format returns 'p' in the path style 's'; 'dot' is the dot-notation of
+// the list that the path is for.
+func (p Path) format(s PathStyle, dot func(Path) string) string {
+	switch s {
+	case JSONPointer:
+		return p.Pointer()
+	case JSONPath:
+		return p.JSONPath()
+	case GoFieldPath:
+		return p.GoPath()
+	}
+	return dot(p)
+}
+
+// keys returns the path in the UnknownJSONKeys dot-notation: lower case
+// keys and array elements numbered from 1.  Map keys are data, so they are
+// not lower cased.
+func (p Path) keys() string {
+	s := make([]string, len(p))
+	for i, seg := range p {
+		switch {
+		case seg.Index >= 0:
+			s[i] = strconv.Itoa(seg.Index + 1)
+		case seg.entry:
+			s[i] = seg.Key
+		default:
+			s[i] = strings.ToLower(seg.Key)
+		}
+	}
+	return strings.Join(s, ".")
+}
+
+// keysAsIs returns the path in the UnknownJSONKeys dot-notation, but with
+// the last key as it is in the JSON object.
+func (p Path) keysAsIs() string {
+	if len(p) == 0 {
+		return ""
+	}
+	k := p[len(p)-1].Key
+	if parent := p[:len(p)-1].keys(); len(parent) > 0 {
+		return parent + "." + k
+	}
+	return k
+}
+
+// members returns the path in the MissingJSONKeys dot-notation: member
+// labels and map keys, without array elements.
+func (p Path) members() string {
+	s := make([]string, 0, len(p))
+	for _, seg := range p {
+		switch {
+		case seg.Index >= 0:
+			continue
+		case seg.field != nil:
+			s = append(s, seg.field.label())
+		default:
+			s = append(s, seg.Key)
+		}
+	}
+	return strings.Join(s, ".")
+}
+
+// existing returns the path in the ExistingJSONKeys dot-notation: member
+// labels with the field name for the final member.
+func (p Path) existing() string {
+	if len(p) == 0 {
+		return ""
+	}
+	name := p[len(p)-1].field.name
+	if parent := p[:len(p)-1].members(); len(parent) > 0 {
+		return parent + "." + name
+	}
+	return name
+}
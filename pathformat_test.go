@@ -0,0 +1,131 @@
+package checkjson
+
+import (
+	"fmt"
+	"testing"
+)
+
+type pfInner struct {
+	JSON  string `json:"json"`
+	Other int
+}
+
+type pfConfig struct {
+	Not   []pfInner
+	Hooks map[string]pfInner `json:"hooks"`
+	Name  string             `json:"myName"`
+}
+
+func TestPathFormat(t *testing.T) {
+	fmt.Println("===================== TestPathFormat ...")
+
+	data := []byte(`{"not":[{"other":1},{"json":"a","x/y":1}],"hooks":{"a.b":{"JSON":"b"}},"MyName":"n","~z":0}`)
+
+	tests := []struct {
+		style                                PathStyle
+		unknown, missing, existing, mismatch string
+	}{
+		{DotNotation,
+			"[not.2.x/y ~z]",
+			"[Not.json Not.Other hooks.a.b.Other]",
+			"[Not Not.Other Not.JSON Hooks hooks.a.b.JSON Name]",
+			"[hooks.a.b.JSON MyName]"},
+		{JSONPointer,
+			"[/not/1/x~1y /~0z]",
+			"[/not/0/json /not/1/Other /hooks/a.b/Other]",
+			"[/not /not/0/other /not/1/json /hooks /hooks/a.b/JSON /MyName]",
+			"[/hooks/a.b/JSON /MyName]"},
+		{JSONPath,
+			"[$.not[1]['x/y'] $['~z']]",
+			"[$.not[0].json $.not[1].Other $.hooks['a.b'].Other]",
+			"[$.not $.not[0].other $.not[1].json $.hooks $.hooks['a.b'].JSON $.MyName]",
+			"[$.hooks['a.b'].JSON $.MyName]"},
+		{GoFieldPath,
+			"[Not[1].x/y ~z]",
+			`[Not[0].JSON Not[1].Other Hooks["a.b"].Other]`,
+			`[Not Not[0].Other Not[1].JSON Hooks Hooks["a.b"].JSON Name]`,
+			`[Hooks["a.b"].JSON Name]`},
+	}
+	for _, test := range tests {
+		r, err := NewChecker(PathFormat(test.style)).Check(data, new(pfConfig))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, l := range []struct {
+			name string
+			got  []string
+			want string
+		}{
+			{"unknown", r.Unknown, test.unknown},
+			{"missing", r.Missing, test.missing},
+			{"existing", r.Existing, test.existing},
+			{"mismatched", r.Mismatched, test.mismatch},
+		} {
+			if s := fmt.Sprint(l.got); s != l.want {
+				t.Fatalf("style %d %s:\n%s !=\n%s", test.style, l.name, s, l.want)
+			}
+		}
+	}
+
+	// the package level setting
+	SetPathFormat(JSONPointer)
+	defer SetPathFormat(DotNotation)
+	keys, err := UnknownJSONKeys(data, new(pfConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(keys); s != "[/not/1/x~1y /~0z]" {
+		t.Fatal(s)
+	}
+}
+
+type pfItem struct {
+	ID    int64
+	Count int
+	Old   string `checkjson:"deprecated=use new"`
+	Host  string `checkjson:"required"`
+}
+
+func TestPathFormatMessages(t *testing.T) {
+	fmt.Println("===================== TestPathFormatMessages ...")
+
+	// the paths in the messages of warnings and errors are in the format too
+	data := []byte(`{"items":[{"host":"a"},{"id":9007199254740993,"count":null,"old":"x"}]}`)
+	type items struct {
+		Items []pfItem `json:"items"`
+	}
+	tests := []struct {
+		style PathStyle
+		path  string
+	}{
+		{DotNotation, "items.2."},
+		{JSONPointer, "/items/1/"},
+		{JSONPath, "$.items[1]."},
+	}
+	for _, test := range tests {
+		c := NewChecker(PathFormat(test.style))
+		r, err := c.Check(data, new(items))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := fmt.Sprintf("[JSON number value: 9007199254740993 for key: %[1]sid - loses precision if decoded via float64 for Go type: int64 "+
+			"null on non-nullable field: %[1]scount of Go type: int - the value is not changed "+
+			"deprecated JSON key: %[1]sold - use new]", test.path)
+		if s := fmt.Sprint(r.Warnings); s != want {
+			t.Fatalf("style %d warnings:\n%s !=\n%s", test.style, s, want)
+		}
+		err = c.RequireKeys(data, new(items))
+		if want := "missing required JSON key: " + test.path + "Host"; err == nil || err.Error() != want {
+			t.Fatalf("style %d RequireKeys: %v != %s", test.style, err, want)
+		}
+	}
+
+	data = []byte(`{"items":[{"host":"a","Host":"b"}]}`)
+	r, err := NewChecker(PathFormat(JSONPointer)).Check(data, new(items))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(r.Warnings); s != "[duplicate JSON key: /items/0/Host - the member already has JSON key: host]" {
+		t.Fatal("warnings:", s)
+	}
+}
@@ -0,0 +1,77 @@
+// pattern.go - path patterns for the keys and members to ignore
+// Copyright © 2016-2019 Charles Banning.  All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package checkjson
+
+import (
+	"strconv"
+	"strings"
+)
+
+// A pattern matches the paths of JSON keys or struct members; it is the
+// compiled form of the dot-notation patterns of SetKeysToIgnore and
+// SetMembersToIgnore.  Segments are matched ignoring case:
+//
+//	name  a JSON key, or a member's JSON tag or field name
+//	3     the 3rd element of a JSON array
+//	*     any one key or array element
+//	**    any number of keys and array elements, including none
+//	[]    any array element; "plugins[]" is the same as "plugins.[]"
+//
+// Array elements can be left out of a pattern - "servers.addr" matches the
+// key "addr" in every element of "servers" - except at the end of a path.
+type pattern []string
+
+// compilePattern returns the pattern for the dot-notation 's'.
+func compilePattern(s string) pattern {
+	var pt pattern
+	for _, seg := range strings.Split(strings.ToLower(s), ".") {
+		if seg != "[]" && strings.HasSuffix(seg, "[]") {
+			pt = append(pt, strings.TrimSuffix(seg, "[]"), "[]")
+			continue
+		}
+		pt = append(pt, seg)
+	}
+	return pt
+}
+
+// match reports whether the pattern matches all of 'p'.
+func (pt pattern) match(p Path) bool {
+	if len(pt) == 0 {
+		return len(p) == 0
+	}
+	if pt[0] == "**" {
+		for i := 0; i <= len(p); i++ {
+			if pt[1:].match(p[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(p) == 0 {
+		return false
+	}
+	if p[0].Index >= 0 {
+		if len(p) > 1 && pt.match(p[1:]) {
+			return true
+		}
+		switch pt[0] {
+		case "*", "[]", strconv.Itoa(p[0].Index + 1):
+			return pt[1:].match(p[1:])
+		}
+		return false
+	}
+	return (pt[0] == "*" || p[0].is(pt[0])) && pt[1:].match(p[1:])
+}
+
+// is reports whether the object key segment is 'name', which is in lower
+// case: the JSON key, or the JSON tag or field name of its member.
+func (seg Segment) is(name string) bool {
+	if strings.ToLower(seg.Key) == name {
+		return true
+	}
+	return seg.field != nil &&
+		(strings.ToLower(seg.field.label()) == name || strings.ToLower(seg.field.name) == name)
+}
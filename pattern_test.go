@@ -0,0 +1,106 @@
+package checkjson
+
+import (
+	"fmt"
+	"testing"
+)
+
+type ptPlugin struct {
+	Name    string
+	Timeout int
+	Config  struct {
+		Level int
+	}
+}
+
+type ptConfig struct {
+	Plugins []ptPlugin
+	Hooks   map[string]ptPlugin
+	Servers []struct {
+		Addr    string
+		Comment string `json:"comment"`
+	}
+}
+
+func TestPatterns(t *testing.T) {
+	fmt.Println("===================== TestPatterns ...")
+
+	data := []byte(`{
+		"plugins":[{"name":"a","config":{"level":1,"x":1},"comment":"c"},{"name":"b","timeout":1,"config":{"y":2}}],
+		"hooks":{"pre":{"name":"p","config":{"z":3}}},
+		"servers":[{"addr":"a","debug":true},{"addr":"b","comment":"c","extra":1}],
+		"comment":"top"
+	}`)
+
+	tests := []struct {
+		keys []string
+		want string
+	}{
+		{nil, "[plugins.1.config.x plugins.1.comment plugins.2.config.y hooks.pre.config.z servers.1.debug servers.2.extra comment]"},
+		{[]string{"comment"}, "[plugins.1.config.x plugins.2.config.y hooks.pre.config.z servers.1.debug servers.2.extra]"},
+		{[]string{"**.comment"}, "[plugins.1.config.x plugins.2.config.y hooks.pre.config.z servers.1.debug servers.2.extra]"},
+		{[]string{"plugins.*.config.*"}, "[plugins.1.comment hooks.pre.config.z servers.1.debug servers.2.extra comment]"},
+		{[]string{"plugins[].config.*", "hooks.*.config.*"}, "[plugins.1.comment servers.1.debug servers.2.extra comment]"},
+		{[]string{"servers.[].debug", "servers.2.extra"}, "[plugins.1.config.x plugins.1.comment plugins.2.config.y hooks.pre.config.z comment]"},
+		{[]string{"servers.extra", "**.config.**"}, "[plugins.1.comment servers.1.debug comment]"},
+		{[]string{"servers.1.*"}, "[plugins.1.config.x plugins.1.comment plugins.2.config.y hooks.pre.config.z servers.2.extra comment]"},
+	}
+	for i, test := range tests {
+		c := NewChecker(KeysToIgnore(test.keys...))
+		keys, err := c.UnknownJSONKeys(data, new(ptConfig))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := fmt.Sprint(keys); s != test.want {
+			t.Fatalf("#%d %v:\n%s !=\n%s", i, test.keys, s, test.want)
+		}
+		// Validate ignores the same keys
+		if err := c.Validate(data, new(ptConfig)); (err == nil) != (len(keys) == 0) {
+			t.Fatalf("#%d %v: %v", i, test.keys, err)
+		}
+	}
+
+	// all of the unknown keys
+	c := NewChecker(KeysToIgnore("**.comment", "**.config.*", "servers.*.debug", "servers.*.extra"))
+	if err := c.Validate(data, new(ptConfig)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMemberPatterns(t *testing.T) {
+	fmt.Println("===================== TestMemberPatterns ...")
+
+	data := []byte(`{"plugins":[{"name":"a"}],"hooks":{"pre":{"name":"p"}},"servers":[{"addr":"a"}]}`)
+
+	tests := []struct {
+		members []string
+		missing string
+		exists  string
+	}{
+		{nil, "[Plugins.Timeout Plugins.Config Hooks.pre.Timeout Hooks.pre.Config Servers.comment]",
+			"[Plugins Plugins.Name Hooks Hooks.pre.Name Servers Servers.Addr]"},
+		{[]string{"**.timeout", "**.comment"}, "[Plugins.Config Hooks.pre.Config]",
+			"[Plugins Plugins.Name Hooks Hooks.pre.Name Servers Servers.Addr]"},
+		{[]string{"plugins.config", "hooks.*.config", "*.timeout", "*.*.timeout"}, "[Servers.comment]",
+			"[Plugins Plugins.Name Hooks Hooks.pre.Name Servers Servers.Addr]"},
+		{[]string{"plugins[].*", "hooks", "servers.comment"}, "[]",
+			"[Plugins Servers Servers.Addr]"},
+	}
+	for i, test := range tests {
+		c := NewChecker(MembersToIgnore(test.members...))
+		mems, err := c.MissingJSONKeys(data, new(ptConfig))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := fmt.Sprint(mems); s != test.missing {
+			t.Fatalf("#%d %v missing:\n%s !=\n%s", i, test.members, s, test.missing)
+		}
+		mems, err = c.ExistingJSONKeys(data, new(ptConfig))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := fmt.Sprint(mems); s != test.exists {
+			t.Fatalf("#%d %v existing:\n%s !=\n%s", i, test.members, s, test.exists)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package checkjson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type posElem struct {
+	Count int `json:"count"`
+}
+
+type posConfig struct {
+	Name  string
+	Elem2 posElem `json:"elem2"`
+	List  []int   `json:"list"`
+	Other string
+}
+
+func TestPositions(t *testing.T) {
+	fmt.Println("===================== TestPositions ...")
+
+	data := []byte(`{
+  "name": "a",
+  "elem2": {"notes": "x",
+    "count": "NaN"},
+  "list": [1, "two"]
+}`)
+	r, err := Check(data, new(posConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range r.Findings {
+		got = append(got, f.String())
+	}
+	want := []string{
+		"3:13: unknown: elem2.notes",
+		"3:13: error: checking subkeys of JSON key: elem2 - no member for JSON key: notes",
+		"4:5: error: checking subkeys of JSON key: elem2 - checking subkeys of JSON key: count - JSON string value: \"NaN\" - can't be decoded to Go type: int",
+		"5:15: error: checking subkeys of JSON key: list - [array element #2] JSON string value: \"two\" - can't be decoded to Go type: int",
+		"2:3: existing: Name",
+		"3:3: existing: Elem2",
+		"4:5: existing: elem2.Count",
+		"5:3: existing: List",
+		"1:1: missing: Other",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("findings:\n%q", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("finding #%d:\n%s !=\n%s", i, got[i], want[i])
+		}
+	}
+
+	// the errors hold the path with the positions
+	err = Validate(data, new(posConfig))
+	var uerr *UnknownKeyError
+	if !errors.As(err, &uerr) {
+		t.Fatalf("not an UnknownKeyError: %v", err)
+	}
+	if pos := uerr.Path.Position(); pos != (Position{Offset: 29, Line: 3, Column: 13}) {
+		t.Fatalf("position: %+v", pos)
+	}
+	if s := uerr.Path[0].Pos.String(); s != "3:3" {
+		t.Fatal("elem2 position:", s)
+	}
+}
+
+func TestSyntaxErrorPosition(t *testing.T) {
+	fmt.Println("===================== TestSyntaxErrorPosition ...")
+
+	data := []byte("{\n  \"a\": 1,\n  \"quote\": missing\n}")
+	_, err := UnknownJSONKeys(data, new(posConfig))
+	var serr *SyntaxError
+	if !errors.As(err, &serr) {
+		t.Fatalf("not a SyntaxError: %v", err)
+	}
+	if serr.Pos.Line != 3 || serr.Pos.Column != 12 {
+		t.Fatalf("position: %+v", serr.Pos)
+	}
+	var jerr *json.SyntaxError
+	if !errors.As(err, &jerr) {
+		t.Fatal("doesn't wrap a *json.SyntaxError")
+	}
+	fmt.Println(err)
+}
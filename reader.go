@@ -0,0 +1,147 @@
+// reader.go - check a JSON object as it is read from an io.Reader
+// Copyright © 2016-2019 Charles Banning.  All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package checkjson
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+	"reflect"
+)
+
+// CheckReader is like Check, but reads the JSON object from 'r'.  The JSON
+// object is read token by token as it is compared with 'val', so it is
+// never held in memory; only the Report grows with the JSON object.
+func CheckReader(r io.Reader, val interface{}) (*Report, error) {
+	return std.CheckReader(r, val)
+}
+
+// CheckReader is like the package level CheckReader function but uses the
+// Checker's settings.
+func (c *Checker) CheckReader(r io.Reader, val interface{}) (*Report, error) {
+	w := c.newWalker(r)
+	if err := w.run(val); err != nil {
+		return nil, w.readError(err)
+	}
+	return w.r, nil
+}
+
+// ValidateReader is like Validate, but reads the JSON object from 'r'.  It
+// stops reading at the first key:value pair that will not decode, so a
+// syntax error after it isn't reported.  The memory used doesn't depend on
+// the size of the JSON object - only on how deeply it is nested - so huge
+// JSON documents can be validated without buffering them:
+//
+//	fd, _ := os.Open("dump.json")
+//	defer fd.Close()
+//	if err := checkjson.ValidateReader(bufio.NewReader(fd), &cfg); err != nil {
+//		...
+//	}
+func ValidateReader(r io.Reader, val interface{}) error {
+	return std.ValidateReader(r, val)
+}
+
+// ValidateReader is like the package level ValidateReader function but
+// uses the Checker's settings.
+func (c *Checker) ValidateReader(r io.Reader, val interface{}) error {
+	var first error
+	err := c.stream(r, val, func(f Finding) bool {
+		if f.Kind == Failure {
+			first = f.Err
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return first
+}
+
+// ValidateAllReader is like ValidateAll, but reads the JSON object from 'r'
+// as ValidateReader does.  Only the errors are held in memory.
+func ValidateAllReader(r io.Reader, val interface{}) error {
+	return std.ValidateAllReader(r, val)
+}
+
+// ValidateAllReader is like the package level ValidateAllReader function
+// but uses the Checker's settings.
+func (c *Checker) ValidateAllReader(r io.Reader, val interface{}) error {
+	var errs []error
+	err := c.stream(r, val, func(f Finding) bool {
+		if f.Kind == Failure {
+			errs = append(errs, f.Err)
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return errors.Join(errs...)
+}
+
+// Findings returns the results of checking the JSON object read from 'r'
+// against 'val' as they are found, without collecting them in a Report.
+// JSON keys, errors and warnings are yielded in document order; the
+// missing and existing members of a JSON object are yielded, in struct
+// definition order, when the end of that JSON object is read - so those of
+// a nested JSON object come before those of the JSON object it is in.  As
+// they have been yielded by then, for a repeated JSON key - reported with a
+// DuplicateKeyWarning - the members of its first value are listed; unlike a
+// Report, they aren't merged with those of the later values.
+//
+// If the JSON object can't be read the sequence ends with the error - e.g.,
+// a *SyntaxError - and a zero Finding; the findings before it have already
+// been yielded.  Reading stops when the loop is left:
+//
+//	for f, err := range checkjson.Findings(r, &cfg) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Printf("%s:%s\n", name, f)
+//	}
+func Findings(r io.Reader, val interface{}) iter.Seq2[Finding, error] {
+	return std.Findings(r, val)
+}
+
+// Findings is like the package level Findings function but uses the
+// Checker's settings.
+func (c *Checker) Findings(r io.Reader, val interface{}) iter.Seq2[Finding, error] {
+	return func(yield func(Finding, error) bool) {
+		err := c.stream(r, val, func(f Finding) bool {
+			return yield(f, nil)
+		})
+		if err != nil {
+			yield(Finding{}, err)
+		}
+	}
+}
+
+// stream reads the JSON object from 'r' and passes the findings for 'val'
+// to 'yield' until it returns false.  It returns an error if the JSON
+// object can't be read.
+func (c *Checker) stream(r io.Reader, val interface{}, yield func(Finding) bool) error {
+	w := &walker{
+		c:     c,
+		s:     newScanner(r),
+		yield: yield,
+		seen:  make(map[reflect.Type]bool),
+	}
+	if err := w.run(val); err != nil {
+		return w.readError(err)
+	}
+	return nil
+}
+
+// readError returns the error for a JSON object that can't be read; as
+// with ResolveJSONError, a *json.SyntaxError is returned as a *SyntaxError.
+func (w *walker) readError(err error) error {
+	if e, ok := err.(*json.SyntaxError); ok {
+		return w.s.src.syntaxError(e)
+	}
+	return err
+}
@@ -0,0 +1,304 @@
+package checkjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+var readerData = []byte(`{
+  "name": "a",
+  "elem2": {"notes": "x",
+    "count": "NaN"},
+  "list": [1, "two"]
+}`)
+
+func TestCheckReader(t *testing.T) {
+	fmt.Println("===================== TestCheckReader ...")
+
+	want, err := Check(readerData, new(posConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// one byte at a time, so tokens are located across reads
+	r, err := CheckReader(iotest.OneByteReader(bytes.NewReader(readerData)), new(posConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(r) != fmt.Sprint(want) {
+		t.Fatalf("report:\n%v !=\n%v", r, want)
+	}
+	fmt.Println("report:", r)
+
+	for _, data := range []string{``, `[{"ok":true}]`, `{"ok":true}}`, `{"ok":tru}`, `{"ok":`} {
+		r, err := CheckReader(strings.NewReader(data), new(posConfig))
+		if err == nil {
+			t.Fatal("no error for:", data)
+		}
+		if r != nil {
+			t.Fatal("report for:", data)
+		}
+		fmt.Println("err ok:", err)
+	}
+}
+
+func TestValidateReader(t *testing.T) {
+	fmt.Println("===================== TestValidateReader ...")
+
+	want := Validate(readerData, new(posConfig))
+	err := ValidateReader(bytes.NewReader(readerData), new(posConfig))
+	if err == nil || err.Error() != want.Error() {
+		t.Fatalf("error:\n%v !=\n%v", err, want)
+	}
+	fmt.Println("err ok:", err)
+
+	want = ValidateAll(readerData, new(posConfig))
+	err = ValidateAllReader(bytes.NewReader(readerData), new(posConfig))
+	if err == nil || err.Error() != want.Error() {
+		t.Fatalf("errors:\n%v !=\n%v", err, want)
+	}
+	fmt.Println("errs ok:", err)
+
+	if err := ValidateReader(strings.NewReader(`{"name":"a","elem2":{"count":1}}`), new(posConfig)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindings(t *testing.T) {
+	fmt.Println("===================== TestFindings ...")
+
+	var got []string
+	for f, err := range Findings(bytes.NewReader(readerData), new(posConfig)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, f.String())
+	}
+	// the members of "elem2" are found at its end
+	want := []string{
+		"3:13: unknown: elem2.notes",
+		"3:13: error: checking subkeys of JSON key: elem2 - no member for JSON key: notes",
+		"4:5: error: checking subkeys of JSON key: elem2 - checking subkeys of JSON key: count - JSON string value: \"NaN\" - can't be decoded to Go type: int",
+		"4:5: existing: elem2.Count",
+		"5:15: error: checking subkeys of JSON key: list - [array element #2] JSON string value: \"two\" - can't be decoded to Go type: int",
+		"2:3: existing: Name",
+		"3:3: existing: Elem2",
+		"5:3: existing: List",
+		"1:1: missing: Other",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("findings:\n%q", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("finding #%d:\n%s !=\n%s", i, got[i], want[i])
+		}
+	}
+
+	// the members of the first value of a repeated key are listed
+	got = got[:0]
+	for f, err := range Findings(strings.NewReader(`{"elem2":{"count":1},"elem2":{}}`), new(posConfig)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, f.String())
+	}
+	if s := strings.Join(got, "|"); s != "1:11: existing: elem2.Count|"+
+		"1:22: warning: duplicate JSON key: elem2 - the member already has JSON key: elem2|"+
+		"1:1: missing: Name|1:2: existing: Elem2|1:1: missing: list|1:1: missing: Other" {
+		t.Fatal("findings:", s)
+	}
+
+	// leaving the loop stops reading
+	n := 0
+	for range Findings(bytes.NewReader(readerData), new(posConfig)) {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Fatal("findings after break:", n)
+	}
+
+	// a syntax error ends the findings
+	var last error
+	got = got[:0]
+	for f, err := range Findings(strings.NewReader("{\n  \"notes\": 1,\n  \"quote\": missing\n}"), new(posConfig)) {
+		if err != nil {
+			last = err
+			continue
+		}
+		got = append(got, f.String())
+	}
+	var serr *SyntaxError
+	if !errors.As(last, &serr) {
+		t.Fatalf("not a SyntaxError: %v", last)
+	}
+	if serr.Pos.Line != 3 || serr.Pos.Column != 12 {
+		t.Fatalf("position: %+v", serr.Pos)
+	}
+	if len(got) != 2 || got[0] != "2:3: unknown: notes" {
+		t.Fatalf("findings before the error:\n%q", got)
+	}
+	fmt.Println("err ok:", last)
+}
+
+func TestValidateReaderStream(t *testing.T) {
+	fmt.Println("===================== TestValidateReaderStream ...")
+
+	type point struct {
+		X, Y int
+	}
+	type points struct {
+		Points []point
+	}
+
+	// an error in the 11th element is returned while the rest is unwritten
+	pr, pw := io.Pipe()
+	done := make(chan error)
+	go func() {
+		_, err := io.WriteString(pw, `{"points":[`)
+		for i := 0; err == nil && i < 1000000; i++ {
+			s := `{"x":1,"y":2},`
+			if i == 10 {
+				s = `{"x":1,"z":2},`
+			}
+			_, err = io.WriteString(pw, s)
+		}
+		done <- err
+	}()
+	err := ValidateReader(pr, new(points))
+	pr.Close()
+	if werr := <-done; werr != io.ErrClosedPipe {
+		t.Fatal("the JSON object was read to its end:", werr)
+	}
+	var uerr *UnknownKeyError
+	if !errors.As(err, &uerr) {
+		t.Fatalf("not an UnknownKeyError: %v", err)
+	}
+	if s := uerr.Path.String(); s != "points.11.z" {
+		t.Fatal("path:", s)
+	}
+	if pos := uerr.Path.Position(); pos.Line != 1 || pos.Column != 12+14*10+7 {
+		t.Fatalf("position: %+v", pos)
+	}
+	fmt.Println("err ok:", err)
+}
+
+func TestSyntaxErrors(t *testing.T) {
+	fmt.Println("===================== TestSyntaxErrors ...")
+
+	// the messages and offsets are those of json.Unmarshal
+	long := `{"list":[` + strings.Repeat(`1234567890,`, 200) + "\n"
+	for _, test := range []struct {
+		data string
+		msg  string
+		off  int64
+		pos  string
+	}{
+		{`{"name":}`, "invalid character '}' looking for beginning of value", 9, "1:9"},
+		{`{"name":"a",}`, "invalid character '}' looking for beginning of object key string", 13, "1:13"},
+		{`{"list":[1,]}`, "invalid character ']' looking for beginning of value", 12, "1:12"},
+		{"{\n  \"list\": [1,\n  ]\n}", "invalid character ']' looking for beginning of value", 19, "3:3"},
+		{`{"elem2":{"count":1,},"name":"a"}`, "invalid character '}' looking for beginning of object key string", 21, "1:21"},
+		{`{,}`, "invalid character ',' looking for beginning of object key string", 2, "1:2"},
+		{`{1:2}`, "invalid character '1' looking for beginning of object key string", 2, "1:2"},
+		{long + `1,]}`, "invalid character ']' looking for beginning of value", int64(len(long)) + 3, "2:3"},
+	} {
+		var v map[string]interface{}
+		jerr := json.Unmarshal([]byte(test.data), &v).(*json.SyntaxError)
+		if jerr.Error() != test.msg || jerr.Offset != test.off {
+			t.Fatalf("json.Unmarshal %.40q: %v at %d", test.data, jerr, jerr.Offset)
+		}
+		for _, err := range []error{
+			Validate([]byte(test.data), new(posConfig)),
+			ValidateReader(iotest.OneByteReader(strings.NewReader(test.data)), new(posConfig)),
+		} {
+			var serr *SyntaxError
+			if !errors.As(err, &serr) {
+				t.Fatalf("%.40q: not a SyntaxError: %v", test.data, err)
+			}
+			pos := fmt.Sprintf("%d:%d", serr.Pos.Line, serr.Pos.Column)
+			if serr.Err.Error() != test.msg || serr.Err.Offset != test.off || pos != test.pos {
+				t.Fatalf("%.40q: %v at %d, %s", test.data, serr.Err, serr.Err.Offset, pos)
+			}
+			if want := ResolveJSONError([]byte(test.data), jerr); err.Error() != want.Error() {
+				t.Fatalf("%.40q:\n%v !=\n%v", test.data, err, want)
+			}
+		}
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	fmt.Println("===================== TestReaderErrors ...")
+
+	// only valid keys, as ValidateReader stops at the first that isn't
+	long := `{"list":[` + strings.Repeat(`1234567890,`, 200) + "\n"
+	for _, data := range []string{
+		``,
+		`  `,
+		`[{"name":"a"}]`,
+		`"name"`,
+		`true`,
+		`{"name":"a"}}`,
+		`{"name":"a"} {}`,
+		`{"name":"a"} x`,
+		`{"name":tru}`,
+		`{"name":`,
+		`{"name":"tr`,
+		`{"name":"a",}`,
+		`{"name":}`,
+		`{"list":[1,]}`,
+		`{"name":"a" "list":[]}`,
+		"{\n  \"list\": [1],\n  \"name\": missing\n}",
+		long + `x]}`,
+		long + `1,]}`,
+		long + `1], "name": missing}`,
+	} {
+		want := fmt.Sprintf("%T %v", Validate([]byte(data), new(posConfig)), Validate([]byte(data), new(posConfig)))
+		err := ValidateReader(iotest.OneByteReader(strings.NewReader(data)), new(posConfig))
+		if got := fmt.Sprintf("%T %v", err, err); got != want {
+			t.Fatalf("ValidateReader %.40q:\n%s !=\n%s", data, got, want)
+		}
+		err = ValidateAllReader(strings.NewReader(data), new(posConfig))
+		if got := fmt.Sprintf("%T %v", err, err); got != want {
+			t.Fatalf("ValidateAllReader %.40q:\n%s !=\n%s", data, got, want)
+		}
+		_, cerr := Check([]byte(data), new(posConfig))
+		_, err = CheckReader(strings.NewReader(data), new(posConfig))
+		if fmt.Sprintf("%T %v", err, err) != fmt.Sprintf("%T %v", cerr, cerr) {
+			t.Fatalf("CheckReader %.40q:\n%v !=\n%v", data, err, cerr)
+		}
+		var serr *SyntaxError
+		var terr *json.UnmarshalTypeError
+		if !errors.As(err, &serr) && !errors.As(err, &terr) {
+			t.Fatalf("%.40q: %T %v", data, err, err)
+		}
+		fmt.Println("err ok:", want)
+	}
+
+	// the io.Reader's error is returned as it is
+	rerr := errors.New("read failed")
+	for _, data := range []string{`{"name":"a"} `, `{"name":"a`} {
+		err := ValidateReader(io.MultiReader(strings.NewReader(data), iotest.ErrReader(rerr)), new(posConfig))
+		if err != rerr {
+			t.Fatalf("%q: %v", data, err)
+		}
+	}
+
+	// as json.Unmarshal does, null is decoded as an empty JSON object
+	if err := ValidateReader(strings.NewReader(`null`), new(posConfig)); err != nil {
+		t.Fatal(err)
+	}
+	r, err := CheckReader(strings.NewReader(` null `), new(posConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(r.Missing); s != "[Name elem2 list Other]" {
+		t.Fatal("missing:", s)
+	}
+}
@@ -0,0 +1,111 @@
+package checkjson
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type tree struct {
+	Name     string
+	Next     *tree
+	Children []*tree
+	Named    map[string]*tree
+}
+
+type menu struct {
+	Label string
+	Items []menuItem
+}
+
+type menuItem struct {
+	Action  string
+	Submenu *menu
+}
+
+func TestRecursivePointer(t *testing.T) {
+	fmt.Println("===================== TestRecursivePointer ...")
+
+	data := []byte(`{"name":"a","next":{"name":"b","next":{"nme":"c"}}}`)
+	err := Validate(data, &tree{})
+	var uerr *UnknownKeyError
+	if !errors.As(err, &uerr) || uerr.Path.String() != "next.next.nme" {
+		t.Fatalf("err: %v", err)
+	}
+	fmt.Println("err ok:", err)
+
+	r, err := Check(data, tree{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "[Next.Next.Name Next.Next.Next Next.Next.Children Next.Next.Named Next.Children Next.Named Children Named]"
+	if s := fmt.Sprint(r.Missing); s != want {
+		t.Fatal("missing:", s, "!=", want)
+	}
+	want = "[Name Next Next.Name Next.Next]"
+	if s := fmt.Sprint(r.Existing); s != want {
+		t.Fatal("existing:", s, "!=", want)
+	}
+}
+
+func TestRecursiveSlice(t *testing.T) {
+	fmt.Println("===================== TestRecursiveSlice ...")
+
+	data := []byte(`{"name":"a","children":[
+		{"name":"b"},
+		{"name":"c","children":[{"name":"d","bad":1},{"name":"e","children":[]}]}
+	]}`)
+	keys, err := UnknownJSONKeys(data, &tree{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(keys); s != "[children.2.children.1.bad]" {
+		t.Fatal("unknown keys:", s)
+	}
+
+	// mutually recursive definitions
+	data = []byte(`{"label":"File","items":[
+		{"action":"open"},
+		{"action":"recent","submenu":{"label":"Recent","items":[{"action":"clear","sub":null}]}}
+	]}`)
+	keys, err = UnknownJSONKeys(data, &menu{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(keys); s != "[items.2.submenu.items.1.sub]" {
+		t.Fatal("unknown keys:", s)
+	}
+}
+
+func TestRecursiveMap(t *testing.T) {
+	fmt.Println("===================== TestRecursiveMap ...")
+
+	data := []byte(`{"name":"a","named":{"left":{"name":"b","named":{"leaf":{"name":"c"}}}}}`)
+	r, err := Check(data, &tree{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Unknown) != 0 {
+		t.Fatal("unknown keys:", r.Unknown)
+	}
+	want := "[Name Named Named.left.Name Named.left.Named Named.left.Named.leaf.Name]"
+	if s := fmt.Sprint(r.Existing); s != want {
+		t.Fatal("existing:", s)
+	}
+}
+
+func TestRecursiveValue(t *testing.T) {
+	fmt.Println("===================== TestRecursiveValue ...")
+
+	// a cycle in the value being checked is only followed as far as the JSON object goes
+	tv := &tree{Name: "loop"}
+	tv.Next = tv
+	data := []byte(`{"next":{"next":{"next":{"name":"a","extra":true}}}}`)
+	keys, err := UnknownJSONKeys(data, tv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(keys); s != "[next.next.next.extra]" {
+		t.Fatal("unknown keys:", s)
+	}
+}
@@ -0,0 +1,48 @@
+// requirekeys.go - check JSON object for required struct members
+// Copyright © 2016-2019 Charles Banning.  All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package checkjson
+
+import (
+	"errors"
+)
+
+// RequireKeys returns an error if the JSON object is missing a key for a
+// member of 'val', which is of type struct, that has the struct tag
+// `checkjson:"required"`; e.g.,
+//
+//	type Server struct {
+//		Host string `json:"host" checkjson:"required"`
+//		Port int    `json:"port,omitempty" checkjson:"required"`
+//		Note string `checkjson:"optional"`
+//	}
+//
+// Unlike MissingJSONKeys the result doesn't depend on "omitempty" tags, nor
+// on any other struct member: the error is nil if all the required members
+// are set by decoding the JSON object.  As with MissingJSONKeys, the members
+// of a nested struct are required only if the JSON object has the key for
+// the nested struct, and members ignored with SetMembersToIgnore are not
+// required.  There's a *MissingKeyError for each missing member, with its
+// dot-notation path; e.g., "servers.2.host".  If more than one member is
+// missing, the error wraps them all - see errors.Join.
+//
+// JSON keys that won't be decoded and JSON values that don't match their
+// member type aren't reported - see Validate.
+func RequireKeys(b []byte, val interface{}) error {
+	return std.RequireKeys(b, val)
+}
+
+// RequireKeys is like the package level RequireKeys function but uses the
+// Checker's settings.
+func (c *Checker) RequireKeys(b []byte, val interface{}) error {
+	w, err := c.walk(b, val)
+	if err != nil {
+		return err
+	}
+	if len(w.reqs) == 1 {
+		return w.reqs[0]
+	}
+	return errors.Join(w.reqs...)
+}
@@ -0,0 +1,86 @@
+package checkjson
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type rqServer struct {
+	Host string `json:"host" checkjson:"required"`
+	Port int    `json:"port,omitempty" checkjson:"required"`
+	Note string `checkjson:"optional"`
+	Tags []string
+}
+
+type rqConfig struct {
+	Name    string     `checkjson:"required"`
+	Servers []rqServer `json:"servers"`
+	Backup  *rqServer  `json:"backup" checkjson:"optional"`
+	Log     struct {
+		Level string `checkjson:"required,norecurse"`
+	}
+}
+
+func TestRequireKeys(t *testing.T) {
+	fmt.Println("===================== TestRequireKeys ...")
+
+	data := []byte(`{"name":"a","servers":[{"host":"h","port":80}],"log":{"level":"debug"}}`)
+	if err := RequireKeys(data, new(rqConfig)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the backup is optional, but not its members
+	data = []byte(`{"servers":[{"host":"h"},{"port":81,"note":"x"}],"backup":{"host":"b"},"log":{}}`)
+	err := RequireKeys(data, new(rqConfig))
+	if err == nil {
+		t.Fatal("no error returned")
+	}
+	var got []string
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var merr *MissingKeyError
+		if !errors.As(e, &merr) {
+			t.Fatalf("not a MissingKeyError: %v", e)
+		}
+		got = append(got, merr.Path.String())
+	}
+	want := "[Name servers.1.port servers.2.host backup.port log.Level]"
+	if s := fmt.Sprint(got); s != want {
+		t.Fatal("required:", s, "!=", want)
+	}
+	fmt.Println("err ok:", err)
+
+	// a single missing member isn't joined
+	data = []byte(`{"name":"a","log":{}}`)
+	err = RequireKeys(data, new(rqConfig))
+	if merr, ok := err.(*MissingKeyError); !ok || merr.Path.String() != "log.Level" {
+		t.Fatalf("err: %v", err)
+	}
+
+	c := NewChecker(MembersToIgnore("log.level"))
+	if err := c.RequireKeys(data, new(rqConfig)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRequiredMissing(t *testing.T) {
+	fmt.Println("===================== TestRequiredMissing ...")
+
+	data := []byte(`{"servers":[{}]}`)
+	mems, err := MissingJSONKeys(data, new(rqConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// "port" is required, whatever its omitempty tag; Note and Backup are optional
+	want := "[Name servers.host servers.port servers.Tags Log]"
+	if s := fmt.Sprint(mems); s != want {
+		t.Fatal("missing:", s, "!=", want)
+	}
+	mems, err = ExistingJSONKeys(data, new(rqConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(mems); s != "[Servers]" {
+		t.Fatal("existing:", s)
+	}
+}
@@ -0,0 +1,319 @@
+// scan.go - read a JSON object token by token
+// Copyright © 2016-2019 Charles Banning.  All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package checkjson
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// scanner reads the JSON values of a JSON object from a json.Decoder as
+// they are needed, so a JSON object can be checked without holding it in
+// memory.  An array or object value is returned open; its elements are
+// read with more, key and value until more reports there are no more, or
+// are skipped with skip.
+//
+// The first error stops the scanner: it then returns null values and no
+// more elements, so a walk of the JSON object unwinds.  An error in the JSON
+// object is the error json.Unmarshal reports when decoding it to a
+// map[string]interface{} value - a *json.SyntaxError or, if it isn't a JSON
+// object, a *json.UnmarshalTypeError - so that a JSON object read from an
+// io.Reader has the same errors as one in a []byte.
+type scanner struct {
+	dec  *json.Decoder
+	src  *source
+	err  error
+	open []jsonKind // the arrays and objects that the last token is in
+	at   int        // where the last token is in the innermost of them
+}
+
+// Where the last token read is in the innermost open array or object.
+const (
+	atStart = iota // it's the '[' or '{'
+	atKey          // it's an object key
+	atValue        // it's, or ends, an element or an object value
+)
+
+// errStopped stops a scanner when the findings are no longer wanted.
+var errStopped = errors.New("stopped")
+
+func newScanner(r io.Reader) *scanner {
+	src := &source{r: r}
+	dec := json.NewDecoder(src)
+	dec.UseNumber()
+	return &scanner{dec: dec, src: src}
+}
+
+// token returns the next token and its position.  The JSON object ends
+// early if there's none.
+func (s *scanner) token() (json.Token, Position) {
+	t, pos := s.next()
+	if s.err == io.EOF || s.err == io.ErrUnexpectedEOF {
+		s.err = newSyntaxError("", s.src.base+len(s.src.buf))
+	}
+	return t, pos
+}
+
+// next returns the next token, if any, and its position.
+func (s *scanner) next() (json.Token, Position) {
+	if s.err != nil {
+		return nil, Position{}
+	}
+	before := int(s.dec.InputOffset())
+	t, err := s.dec.Token()
+	if err != nil {
+		if e, ok := err.(*json.SyntaxError); ok {
+			err = s.syntaxError(e, before)
+		}
+		s.err = err
+		return nil, Position{}
+	}
+	s.track(t)
+	return t, s.src.position(s.src.start(before))
+}
+
+// track records where the token 't' that has been read leaves the scanner.
+func (s *scanner) track(t json.Token) {
+	switch t {
+	case json.Delim('{'):
+		s.open, s.at = append(s.open, objectKind), atStart
+	case json.Delim('['):
+		s.open, s.at = append(s.open, arrayKind), atStart
+	case json.Delim('}'), json.Delim(']'):
+		s.open, s.at = s.open[:len(s.open)-1], atValue
+	default:
+		if n := len(s.open); n > 0 && s.open[n-1] == objectKind && s.at != atKey {
+			s.at = atKey
+		} else {
+			s.at = atValue
+		}
+	}
+}
+
+// prefix returns JSON text that leaves json.Unmarshal where the last token
+// read leaves the scanner: in the same arrays and objects, and at the same
+// point in the innermost of them.
+func (s *scanner) prefix() string {
+	var b strings.Builder
+	for i, k := range s.open {
+		inner := i == len(s.open)-1
+		switch {
+		case k == arrayKind:
+			b.WriteString("[")
+			if inner && s.at == atValue {
+				b.WriteString("0")
+			}
+		case !inner:
+			b.WriteString(`{"":`)
+		case s.at == atStart:
+			b.WriteString("{")
+		case s.at == atKey:
+			b.WriteString(`{""`)
+		default:
+			b.WriteString(`{"":0`)
+		}
+	}
+	return b.String()
+}
+
+// syntaxError returns the *json.SyntaxError that json.Unmarshal reports for
+// the error 'e' of the json.Decoder in the JSON text after the offset 'off',
+// the end of the last token read.  The Decoder's messages and offsets aren't
+// those of json.Unmarshal - e.g., "missing value after object key" - so the
+// text is checked again by json.Unmarshal, after the prefix that puts it
+// where the last token left the scanner.
+func (s *scanner) syntaxError(e *json.SyntaxError, off int) *json.SyntaxError {
+	p := s.prefix()
+	var v interface{}
+	err := json.Unmarshal(append([]byte(p), s.src.buf[off-s.src.base:]...), &v)
+	u, ok := err.(*json.SyntaxError)
+	if !ok || u.Error() == errEnd.Error() || int(u.Offset) <= len(p) {
+		return e // not found in the text that has been read
+	}
+	u.Offset += int64(off - len(p))
+	return u
+}
+
+// errEnd is the error json.Unmarshal reports for JSON text that ends early.
+var errEnd = newSyntaxError("", 0)
+
+// value returns the next JSON value.
+func (s *scanner) value() *node {
+	t, pos := s.token()
+	n := &node{kind: nullKind, pos: pos}
+	switch v := t.(type) {
+	case json.Delim:
+		switch v {
+		case '{':
+			n.kind, n.open = objectKind, true
+		case '[':
+			n.kind, n.open = arrayKind, true
+		default:
+			s.err = errors.New("unexpected JSON delimiter: " + v.String())
+		}
+	case string:
+		n.kind, n.text = stringKind, v
+	case json.Number:
+		n.kind, n.text = numberKind, string(v)
+	case bool:
+		n.kind, n.text = boolKind, "false"
+		if v {
+			n.text = "true"
+		}
+	}
+	return n
+}
+
+// top returns the top-level value, which must be a JSON object or null.
+func (s *scanner) top() *node {
+	n := s.value()
+	if s.err == nil && n.kind != objectKind && n.kind != nullKind {
+		s.err = &json.UnmarshalTypeError{
+			Value:  n.kind.String(),
+			Type:   reflect.TypeOf(map[string]interface{}(nil)),
+			Offset: s.dec.InputOffset(),
+		}
+	}
+	return n
+}
+
+// key returns the next key of an open object and its position.
+func (s *scanner) key() (string, Position) {
+	t, pos := s.token()
+	k, _ := t.(string)
+	return k, pos
+}
+
+// more reports whether the open array or object 'n' has another element;
+// if not, the closing delimiter is read.
+func (s *scanner) more(n *node) bool {
+	if !n.open {
+		return false
+	}
+	if s.err == nil && s.dec.More() {
+		return true
+	}
+	n.open = false
+	s.token() // ']' or '}'
+	return false
+}
+
+// skip reads the rest of the array or object 'n', if it's open.
+func (s *scanner) skip(n *node) {
+	for s.more(n) {
+		if n.kind == objectKind {
+			s.key()
+		}
+		s.skip(s.value())
+	}
+}
+
+// end checks that there's only white space after the top-level value.
+func (s *scanner) end() {
+	if s.err != nil {
+		return
+	}
+	before := int(s.dec.InputOffset())
+	_, err := s.dec.Token()
+	if err == io.EOF {
+		return
+	}
+	off := s.src.start(before)
+	if off-s.src.base >= len(s.src.buf) {
+		s.err = err // the io.Reader failed
+		return
+	}
+	s.err = newSyntaxError("0"+string(s.src.buf[off-s.src.base]), off+1)
+}
+
+// newSyntaxError returns the *json.SyntaxError that json.Unmarshal reports
+// for the JSON text 'b', but at the offset 'off'.  (A json.SyntaxError's
+// message can't be set otherwise.)
+func newSyntaxError(b string, off int) *json.SyntaxError {
+	var v interface{}
+	e := json.Unmarshal([]byte(b), &v).(*json.SyntaxError)
+	e.Offset = int64(off)
+	return e
+}
+
+// fill reads the elements of the open array or object 'n' into 'n', so
+// that it is the tree of the JSON value.
+func (s *scanner) fill(n *node) {
+	for s.more(n) {
+		if n.kind == objectKind {
+			k, pos := s.key()
+			n.keys = append(n.keys, k)
+			n.kpos = append(n.kpos, pos)
+		}
+		e := s.value()
+		s.fill(e)
+		n.elems = append(n.elems, e)
+	}
+}
+
+// source is the io.Reader of a scanner's json.Decoder.  It keeps the bytes
+// that the decoder has read beyond the last token that was located, so that
+// the next token can be located, and maxContext bytes before it for the
+// context of a syntax error; the bytes before those are dropped.
+type source struct {
+	r     io.Reader
+	buf   []byte // the bytes read from offset 'base'
+	base  int
+	off   int // offset of the last token that was located
+	lines int // number of lines before 'off'
+	bol   int // offset of the start of the line with 'off'
+}
+
+func (s *source) Read(b []byte) (int, error) {
+	n, err := s.r.Read(b)
+	s.buf = append(s.buf, b[:n]...)
+	return n, err
+}
+
+// start returns the offset of the first byte at or after 'off' that isn't
+// white space or a ',' or ':' separator: the start of the next token.
+func (s *source) start(off int) int {
+	for i := off - s.base; i < len(s.buf) && strings.IndexByte(" \t\r\n,:", s.buf[i]) >= 0; i++ {
+		off++
+	}
+	return off
+}
+
+// position returns the Position of the byte offset 'off' and drops the
+// bytes that are no longer needed; 'off' can't be before that of the last
+// call.
+func (s *source) position(off int) Position {
+	end := off - s.base
+	if end > len(s.buf) {
+		end = len(s.buf)
+	}
+	for i := s.off - s.base; i < end; i++ {
+		if s.buf[i] == '\n' {
+			s.lines++
+			s.bol = s.base + i + 1
+		}
+	}
+	s.off = off
+	if i := off - maxContext - s.base; i > 0 {
+		s.buf = s.buf[i:]
+		s.base += i
+	}
+	return Position{off, s.lines + 1, off - s.bol + 1}
+}
+
+// syntaxError returns the *SyntaxError for 'e', as ResolveJSONError does
+// for the JSON object.
+func (s *source) syntaxError(e *json.SyntaxError) *SyntaxError {
+	off := int(e.Offset) - 1 // the offending byte
+	if off < s.off {
+		off = s.off
+	}
+	ctx := errorContext(s.buf[:int(e.Offset)-s.base])
+	return &SyntaxError{e, ctx, s.position(off)}
+}
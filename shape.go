@@ -0,0 +1,70 @@
+// shape.go - members that decode themselves
+// Copyright © 2016-2019 Charles Banning.  All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package checkjson
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// A JSONShaper describes the JSON value that its type is decoded from.
+//
+// Members whose type, or a pointer to it, implements json.Unmarshaler or
+// encoding.TextUnmarshaler decode themselves, perhaps from a JSON value of a
+// completely different shape, so their JSON values are not checked.  Such a
+// type can implement JSONShaper to have its JSON value checked against the
+// value returned by JSONShape; e.g., a struct type or a map[string]T.  If
+// JSONShape returns nil the JSON value is not checked.
+//
+// JSONShape may be called on a zero value of the type.
+type JSONShaper interface {
+	JSONShape() interface{}
+}
+
+var (
+	jsonShaperType      = reflect.TypeOf((*JSONShaper)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// unmarshaler reports whether encoding/json leaves decoding a value of type
+// 't' to the type's UnmarshalJSON or UnmarshalText method.  As with
+// encoding/json the methods of a pointer to 't' are included.
+func unmarshaler(t reflect.Type) bool {
+	if t.Kind() == reflect.Interface {
+		return false // the dynamic value is checked
+	}
+	if t.Kind() != reflect.Ptr {
+		t = reflect.PointerTo(t)
+	}
+	return t.Implements(jsonUnmarshalerType) || t.Implements(textUnmarshalerType)
+}
+
+// shape returns the JSONShape value for 'v' if its type, or a pointer to it,
+// implements JSONShaper.  A nil shape is returned as the zero Value.
+func shape(v reflect.Value) (reflect.Value, bool) {
+	t := v.Type()
+	var s JSONShaper
+	switch {
+	case t.Kind() == reflect.Interface:
+		return reflect.Value{}, false
+	case t.Implements(jsonShaperType):
+		if t.Kind() == reflect.Ptr && (v.IsNil() || !v.CanInterface()) {
+			v = reflect.New(t.Elem())
+		} else if !v.CanInterface() {
+			v = reflect.Zero(t)
+		}
+		s = v.Interface().(JSONShaper)
+	case reflect.PointerTo(t).Implements(jsonShaperType):
+		pv := reflect.New(t)
+		if v.CanInterface() {
+			pv.Elem().Set(v)
+		}
+		s = pv.Interface().(JSONShaper)
+	default:
+		return reflect.Value{}, false
+	}
+	return reflect.ValueOf(s.JSONShape()), true
+}
@@ -0,0 +1,123 @@
+package checkjson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// shDuration is decoded from a JSON string, e.g. "5s".
+type shDuration struct {
+	time.Duration
+}
+
+func (d *shDuration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	var err error
+	d.Duration, err = time.ParseDuration(s)
+	return err
+}
+
+// shLevel has a value receiver.
+type shLevel struct {
+	n int
+}
+
+func (l shLevel) UnmarshalText(b []byte) error {
+	return nil
+}
+
+// shRange is decoded from "min-max" or {"min":n,"max":n}.
+type shRange struct {
+	Min, Max int
+}
+
+func (r *shRange) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		_, err = fmt.Sscanf(strings.Replace(s, "-", " ", 1), "%d %d", &r.Min, &r.Max)
+		return err
+	}
+	var v struct{ Min, Max int }
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	r.Min, r.Max = v.Min, v.Max
+	return nil
+}
+
+func (r shRange) JSONShape() interface{} {
+	return struct{ Min, Max int }{}
+}
+
+// shAny declines to describe its JSON value.
+type shAny struct {
+	Value string
+}
+
+func (a *shAny) JSONShape() interface{} {
+	return nil
+}
+
+type shConfig struct {
+	Timeout shDuration
+	Started time.Time
+	Level   *shLevel
+	Raw     json.RawMessage
+	Ports   shRange
+	Spare   *shRange
+	Any     shAny
+}
+
+func TestUnmarshaler(t *testing.T) {
+	fmt.Println("===================== TestUnmarshaler ...")
+
+	data := []byte(`{
+		"timeout":"5s",
+		"started":"2019-01-02T15:04:05Z",
+		"level":"debug",
+		"raw":{"anything":[1,2,3]},
+		"ports":{"min":80,"max":90}
+	}`)
+	if err := decodeStrict(data, new(shConfig)); err != nil {
+		t.Fatal("encoding/json:", err)
+	}
+	if err := Validate(data, new(shConfig)); err != nil {
+		t.Fatal(err)
+	}
+	r, err := Check(data, new(shConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(r.Missing); s != "[Spare Any]" {
+		t.Fatal("missing:", s)
+	}
+}
+
+func TestJSONShape(t *testing.T) {
+	fmt.Println("===================== TestJSONShape ...")
+
+	data := []byte(`{"ports":{"min":80,"mx":90},"spare":{"max":1,"other":2},"any":{"x":1}}`)
+	keys, err := UnknownJSONKeys(data, new(shConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(keys); s != "[ports.mx spare.other]" {
+		t.Fatal("unknown:", s)
+	}
+
+	// shRange also decodes from a string, but its shape is an object
+	data = []byte(`{"ports":"80-90"}`)
+	err = Validate(data, new(shConfig))
+	var oerr *NotObjectError
+	if !errors.As(err, &oerr) || oerr.Path.String() != "ports" {
+		t.Fatalf("err: %v", err)
+	}
+	fmt.Println("err ok:", err)
+}
@@ -0,0 +1,93 @@
+package checkjson
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type scServer struct {
+	Host    string
+	Port    int    `json:"port"`
+	TimeOut string `json:"timeOut"`
+}
+
+type scConfig struct {
+	Name    string
+	Servers []scServer `json:"servers"`
+}
+
+func TestStrictCase(t *testing.T) {
+	fmt.Println("===================== TestStrictCase ...")
+
+	data := []byte(`{"Name":"a","servers":[{"Host":"h","port":1,"timeOut":"1s"},{"host":"h","Port":2,"timeout":"2s"}]}`)
+	c := NewChecker(StrictCase(true))
+
+	r, err := c.Check(data, new(scConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Unknown) != 0 {
+		t.Fatal("unknown:", r.Unknown)
+	}
+	want := "[{servers.2.host Host} {servers.2.Port port} {servers.2.timeout timeOut}]"
+	if s := fmt.Sprint(r.CaseMismatched); s != want {
+		t.Fatal("case mismatched:", s, "!=", want)
+	}
+	want = "[servers.Host servers.port servers.timeout]"
+	if s := fmt.Sprint(r.Missing); s != want {
+		t.Fatal("missing:", s, "!=", want)
+	}
+
+	err = c.Validate(data, new(scConfig))
+	var cerr *CaseMismatchError
+	if !errors.As(err, &cerr) || cerr.Path.String() != "servers.2.host" || cerr.Name != "Host" {
+		t.Fatalf("err: %v", err)
+	}
+	fmt.Println("err ok:", err)
+
+	// the default is to match case-insensitively, as encoding/json does
+	r, err = Check(data, new(scConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.CaseMismatched) != 0 || len(r.Missing) != 0 {
+		t.Fatal("report:", r.CaseMismatched, r.Missing)
+	}
+	if err := Validate(data, new(scConfig)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStrictCaseCamel(t *testing.T) {
+	fmt.Println("===================== TestStrictCaseCamel ...")
+
+	// see camelcase_test.go
+	type test struct {
+		Ok     bool
+		Whynot string `json:"whynot"`
+	}
+	data := []byte(`{"Ok":false, "whyNot":"it's not a test"}`)
+	c := NewChecker(StrictCase(true))
+	err := c.Validate(data, test{})
+	if err == nil {
+		t.Fatal("no error returned")
+	}
+	if s := err.Error(); s != "JSON key: whyNot - does not match the case of member: whynot" {
+		t.Fatal("err:", s)
+	}
+	keys, err := c.UnknownJSONKeys(data, test{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 0 {
+		t.Fatal("unknown:", keys)
+	}
+	mems, err := c.ExistingJSONKeys(data, test{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(mems); s != "[Ok]" {
+		t.Fatal("existing:", s)
+	}
+}
@@ -0,0 +1,79 @@
+package checkjson
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type soConfig struct {
+	ID      int64   `json:"id,string"`
+	Port    *uint16 `json:"port,string"`
+	Ratio   float32 `json:",string"`
+	Enabled bool    `json:"enabled,string,omitempty"`
+	Name    string  `json:"name,string"`
+	Tags    []int   `json:"tags,string"` // the option doesn't apply
+}
+
+func TestStringOption(t *testing.T) {
+	fmt.Println("===================== TestStringOption ...")
+
+	tests := []string{
+		`{"id":"123","port":"8080","ratio":"0.5","enabled":"true","name":"\"a\""}`,
+		`{"id":null,"port":null,"enabled":"null","name":"null"}`,
+		`{"tags":[1,2]}`,
+		`{"id":123}`,
+		`{"id":"12a"}`,
+		`{"id":"1.5"}`,
+		`{"id":"99999999999999999999"}`,
+		`{"id":"-1"}`,
+		`{"id":" 1"}`,
+		`{"id":"01"}`,
+		`{"port":"-1"}`,
+		`{"port":"65536"}`,
+		`{"ratio":"1e3"}`,
+		`{"ratio":"x"}`,
+		`{"enabled":"yes"}`,
+		`{"enabled":true}`,
+		`{"enabled":"TRUE"}`,
+		`{"name":"a"}`,
+		`{"name":"\"a"}`,
+		`{"name":["a"]}`,
+		`{"name":"\"a\" "}`,
+		`{"name":" \"a\""}`,
+		`{"name":"\"a\\u0041\""}`,
+		`{"name":"123"}`,
+		`{"id":"+1"}`,
+		`{"id":"1_0"}`,
+		`{"id":"0x10"}`,
+		`{"id":""}`,
+		`{"id":"null "}`,
+		`{"ratio":"-Inf"}`,
+		`{"ratio":"1e39"}`,
+		`{"enabled":"tru"}`,
+		`{"enabled":"1"}`,
+	}
+	for i, test := range tests {
+		data := []byte(test)
+		jerr := decodeStrict(data, new(soConfig))
+		verr := Validate(data, new(soConfig))
+		if (jerr == nil) != (verr == nil) {
+			t.Fatalf("#%d %s:\nencoding/json: %v\ncheckjson: %v", i, data, jerr, verr)
+		}
+	}
+
+	data := []byte(`{"id":123,"port":"8o80","name":"a"}`)
+	err := ValidateAll(data, new(soConfig))
+	var got []string
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var serr *StringOptionError
+		if !errors.As(e, &serr) {
+			t.Fatalf("not a StringOptionError: %v", e)
+		}
+		got = append(got, fmt.Sprintf("%s:%s:%v", serr.Path, serr.Value, serr.Err != nil))
+	}
+	if s := fmt.Sprint(got); s != `[id:123:false port:"8o80":true name:"a":true]` {
+		t.Fatal("errors:", s)
+	}
+	fmt.Println("err ok:", err)
+}
@@ -0,0 +1,116 @@
+// suggest.go - "did you mean" suggestions for unknown JSON keys
+// Copyright © 2016-2019 Charles Banning.  All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package checkjson
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxSuggestions is the most JSON names suggested for an unknown JSON key.
+const maxSuggestions = 3
+
+// suggest returns the JSON names of the members of the struct that the
+// unknown JSON key 'k' may have been meant for, best first.  Names are
+// ranked by their edit distance from 'k', ignoring case and counting a
+// transposition of adjacent letters as one edit; snake_case, kebab-case and
+// camelCase spellings of a name - "timeout_ms", "timeout-ms", "timeoutMs" -
+// are as close as it gets.  Names that are too far from 'k' to be a
+// misspelling are not suggested: the distance can be at most a third of the
+// length of 'k', or 1, and must be less than the lengths of both 'k' and the
+// name - so, e.g., "x" isn't taken for a misspelling of "y".  The 'skip'
+// function, if not nil, reports members that are not to be suggested.
+func (sf *structFields) suggest(k string, skip func(f *field) bool) []string {
+	type candidate struct {
+		name string
+		dist int
+	}
+	var cands []candidate
+	fk, sk := strings.ToLower(k), squash(k)
+	n := utf8.RuneCountInString(k)
+	limit := n / 3
+	if limit < 1 {
+		limit = 1
+	}
+	for i := range sf.list {
+		f := &sf.list[i]
+		if f.ignored || f.deprecated || (skip != nil && skip(f)) {
+			continue
+		}
+		name := f.jsonName()
+		d := 0
+		if sk != squash(name) {
+			d = editDistance(fk, strings.ToLower(name))
+			if ds := editDistance(sk, squash(name)); ds < d {
+				d = ds
+			}
+		}
+		if d <= limit && d < n && d < utf8.RuneCountInString(name) {
+			cands = append(cands, candidate{name, d})
+		}
+	}
+	// ties are left in struct definition order
+	sort.SliceStable(cands, func(i, j int) bool {
+		return cands[i].dist < cands[j].dist
+	})
+	if len(cands) > maxSuggestions {
+		cands = cands[:maxSuggestions]
+	}
+	if len(cands) == 0 {
+		return nil
+	}
+	names := make([]string, len(cands))
+	for i, c := range cands {
+		names[i] = c.name
+	}
+	return names
+}
+
+// squash returns 's' in lower case without word separators, so that the
+// snake_case, kebab-case and camelCase spellings of a name are the same.
+func squash(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(s))
+}
+
+// editDistance returns the number of single rune insertions, deletions,
+// substitutions and transpositions of adjacent runes that change 'a' to 'b'
+// - the optimal string alignment distance.
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	// rows i-2, i-1 and i of the distance matrix
+	d0, d1, d2 := make([]int, len(t)+1), make([]int, len(t)+1), make([]int, len(t)+1)
+	for j := range d1 {
+		d1[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		d2[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			d := d1[j-1] + cost
+			if d1[j]+1 < d {
+				d = d1[j] + 1
+			}
+			if d2[j-1]+1 < d {
+				d = d2[j-1] + 1
+			}
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] && d0[j-2]+1 < d {
+				d = d0[j-2] + 1
+			}
+			d2[j] = d
+		}
+		d0, d1, d2 = d1, d2, d0
+	}
+	return d1[len(t)]
+}
@@ -0,0 +1,103 @@
+package checkjson
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type sgElem struct {
+	Name      string
+	Note      string `json:"note"`
+	Notify    bool   `json:"notify"`
+	TimeoutMs int    `json:"timeout_ms"`
+	Timeout   int    `json:"timeout"`
+	Legacy    int    `json:"legacy" checkjson:"deprecated"`
+}
+
+type sgConfig struct {
+	Elem1 sgElem
+	Elem2 []sgElem
+}
+
+func TestSuggestions(t *testing.T) {
+	fmt.Println("===================== TestSuggestions ...")
+
+	data := []byte(`{"elem1":{"timout":1,"timeoutMs":2,"nmae":"a","legcy":1,"xyzzy":0},"elem2":[{"notes":"a"}]}`)
+	r, err := Check(data, new(sgConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(r.Unknown); s != "[elem1.timout elem1.timeoutms elem1.nmae elem1.legcy elem1.xyzzy elem2.1.notes]" {
+		t.Fatal("unknown:", s)
+	}
+	want := map[string]string{
+		"elem1.timout":    "[timeout]",
+		"elem1.timeoutms": "[timeout_ms timeout]",
+		"elem1.nmae":      "[Name]",
+		"elem2.1.notes":   "[note]",
+	}
+	if len(r.Suggestions) != len(want) {
+		t.Fatal("suggestions:", r.Suggestions)
+	}
+	for k, v := range want {
+		if s := fmt.Sprint(r.Suggestions[k]); s != v {
+			t.Fatal(k, "suggestions:", s, "!=", v)
+		}
+	}
+
+	err = Validate([]byte(`{"elem1":{"timout":1}}`), new(sgConfig))
+	var uerr *UnknownKeyError
+	if !errors.As(err, &uerr) {
+		t.Fatalf("not an UnknownKeyError: %v", err)
+	}
+	if s := uerr.Error(); s != "no member for JSON key: timout - did you mean: timeout?" {
+		t.Fatal(s)
+	}
+
+	// ignored members aren't suggested
+	c := NewChecker(MembersToIgnore("elem1.timeoutms"))
+	r, err = c.Check([]byte(`{"elem1":{"timeoutMs":1}}`), new(sgConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(r.Suggestions["elem1.timeoutms"]); s != "[timeout]" {
+		t.Fatal("suggestions:", s)
+	}
+	// short keys aren't taken for misspellings of other short names
+	type short struct {
+		X  int
+		Y  int
+		ID string `json:"id"`
+	}
+	r, err = Check([]byte(`{"z":1,"y":2,"i":3,"di":4,"ip":5}`), new(short))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(r.Unknown); s != "[z i di ip]" {
+		t.Fatal("unknown:", s)
+	}
+	if s := fmt.Sprint(r.Suggestions); s != "map[di:[id] ip:[id]]" {
+		t.Fatal("suggestions:", s)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	fmt.Println("===================== TestEditDistance ...")
+
+	for _, test := range []struct {
+		a, b string
+		d    int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"timout", "timeout", 1},
+		{"nmae", "name", 1},
+		{"kitten", "sitting", 3},
+		{"été", "ete", 2},
+	} {
+		if d := editDistance(test.a, test.b); d != test.d {
+			t.Fatalf("%q %q: %d != %d", test.a, test.b, d, test.d)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package checkjson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type tyServer struct {
+	Host    string
+	Port    int
+	Enabled bool
+	Weight  float64
+	Tags    []string
+	Limits  map[string]uint8
+	Size    json.Number
+	Any     interface{}
+	Next    *tyServer
+}
+
+func TestTypeMismatch(t *testing.T) {
+	fmt.Println("===================== TestTypeMismatch ...")
+
+	data := []byte(`{
+		"host":"a",
+		"port":"8080",
+		"enabled":1,
+		"weight":true,
+		"tags":["a",2,null],
+		"limits":{"cpu":{"max":1}},
+		"size":12.5,
+		"any":[1,"a",{}],
+		"next":{"host":["b"],"port":null}
+	}`)
+	if err := json.Unmarshal(data, new(tyServer)); err == nil {
+		t.Fatal("encoding/json decoded data")
+	}
+
+	err := Validate(data, new(tyServer))
+	var terr *TypeMismatchError
+	if !errors.As(err, &terr) {
+		t.Fatalf("not a TypeMismatchError: %v", err)
+	}
+	if terr.Path.String() != "port" || terr.Type.String() != "int" || terr.Kind != "string" || terr.Value != `"8080"` {
+		t.Fatalf("TypeMismatchError: %#v", terr)
+	}
+	want := `checking subkeys of JSON key: port - JSON string value: "8080" - can't be decoded to Go type: int`
+	if err.Error() != want {
+		t.Fatal("err:", err)
+	}
+
+	err = ValidateAll(data, new(tyServer))
+	var got []string
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		if !errors.As(e, &terr) {
+			t.Fatalf("not a TypeMismatchError: %v", e)
+		}
+		got = append(got, fmt.Sprintf("%s:%s:%s:%s", terr.Path, terr.Type, terr.Kind, terr.Value))
+	}
+	want = `[port:int:string:"8080" enabled:bool:number:1 weight:float64:bool:true tags.2:string:number:2 limits.cpu:uint8:object:{...} next.host:string:array:[...]]`
+	if s := fmt.Sprint(got); s != want {
+		t.Fatal("errors:", s, "!=", want)
+	}
+	fmt.Println("err ok:", err)
+
+	// as with encoding/json, a JSON string for a json.Number must hold a number
+	for _, size := range []string{`"abc"`, `""`, `"1."`, `"01"`, `" 1"`, `"1e"`, `"--1"`} {
+		data := []byte(`{"size":` + size + `}`)
+		if json.Unmarshal(data, new(tyServer)) == nil {
+			t.Fatal("encoding/json decoded size:", size)
+		}
+		if !errors.As(Validate(data, new(tyServer)), &terr) || terr.Type != reflect.TypeOf(json.Number("")) {
+			t.Fatal("no TypeMismatchError for size:", size)
+		}
+	}
+	for _, size := range []string{`"0"`, `"-1.5e+3"`, `"12E-2"`} {
+		data := []byte(`{"size":` + size + `}`)
+		if err := json.Unmarshal(data, new(tyServer)); err != nil {
+			t.Fatal("encoding/json:", err)
+		}
+		if err := Validate(data, new(tyServer)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the keys are all known
+	keys, err := UnknownJSONKeys(data, new(tyServer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 0 {
+		t.Fatal("unknown:", keys)
+	}
+	// a value that isn't an array or object, as the member needs, is unknown
+	// and the member is existing - not missing as well
+	data = []byte(`{"host":"a","tags":{"a":"b"},"limits":[1],"next":"x"}`)
+	r, err := Check(data, new(tyServer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(r.Unknown); s != "[tags limits next]" {
+		t.Fatal("unknown:", s)
+	}
+	if s := fmt.Sprint(r.Missing, r.Existing); s != "[Port Enabled Weight Size Any] [Host Tags Limits Next]" {
+		t.Fatal("missing, existing:", s)
+	}
+}
+
+func TestTypeMatch(t *testing.T) {
+	fmt.Println("===================== TestTypeMatch ...")
+
+	data := []byte(`{
+		"host":"a",
+		"port":8080,
+		"enabled":false,
+		"weight":1e3,
+		"tags":["a",null],
+		"limits":{"cpu":1,"mem":null},
+		"size":"12.5",
+		"any":{"x":[1,"a"]},
+		"next":null
+	}`)
+	if err := decodeStrict(data, new(tyServer)); err != nil {
+		t.Fatal("encoding/json:", err)
+	}
+	if err := ValidateAll(data, new(tyServer)); err != nil {
+		t.Fatal(err)
+	}
+}
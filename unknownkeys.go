@@ -5,13 +5,6 @@
 
 package checkjson
 
-import (
-	"encoding/json"
-	"reflect"
-	"strconv"
-	"strings"
-)
-
 // UnknownJSONKeys returns a slice of the JSON object keys that will not
 // be decoded to a member of 'val', which is of type struct.  For nested
 // JSON objects the keys are reported using dot-notation.
@@ -31,136 +24,60 @@ import (
 // dot-notation; so if the error is deep in a JSON object it may be hard to locate.
 // (NOTE: as of 3/5/19, change 145218, the stdlib now reports key using
 // dot-notation, as here.)
+//
+// The member names that an unknown key may have been meant for - e.g.,
+// "timeout" for "timout" - are in Report.Suggestions; see Check.  Validate
+// includes them in the error for the key.
 func UnknownJSONKeys(b []byte, val interface{}) ([]string, error) {
-	s := make([]string, 0)
-	m := make(map[string]interface{})
-	if err := json.Unmarshal(b, &m); err != nil {
-		return nil, ResolveJSONError(b, err)
-	}
-	if err := checkAllFields(m, reflect.ValueOf(val), &s, ""); err != nil {
-		return s, err
-	}
-	return s, nil
+	return std.UnknownJSONKeys(b, val)
 }
 
-func checkAllFields(mv interface{}, val reflect.Value, s *[]string, key string) error {
-	var tkey string
-
-	// 1. Convert any pointer value.
-	if val.Kind() == reflect.Ptr {
-		val = reflect.Indirect(val) // convert ptr to struct
-	}
-	// zero Value?
-	if !val.IsValid() {
-		return nil
-	}
-	typ := val.Type()
-
-	// json.RawMessage is a []byte/[]uint8 and has Kind() == reflect.Slice
-	if typ.Name() == "RawMessage" {
-		return nil
-	}
-
-	// 2. If its a slice then 'mv' should hold a []interface{} value.
-	//    Loop through the members of 'mv' and see that they are valid relative
-	//    to the <T> of val []<T>.
-	if typ.Kind() == reflect.Slice {
-		tval := typ.Elem()
-		if tval.Kind() == reflect.Ptr {
-			tval = tval.Elem()
-		}
-		// slice may be nil, so create a Value of it's type
-		// 'mv' must be of type []interface{}
-		sval := reflect.New(tval)
-		slice, ok := mv.([]interface{})
-		if !ok {
-			*s = append(*s, key)
-			return nil
-		}
-		// 2.1. Check members of JSON array.
-		//      This forces all of them to be regular and w/o typos in key labels.
-		for n, sl := range slice {
-			if key == "" {
-				tkey = strconv.Itoa(n + 1)
-			} else {
-				tkey = key + "." + strconv.Itoa(n+1)
-			}
-			_ = checkAllFields(sl, sval, s, tkey)
-		}
-		return nil // done with reflect.Slice value
-	}
-
-	// 3a. Ignore anything that's not a struct.
-	if typ.Kind() != reflect.Struct {
-		return nil // just ignore it - don't look for k:v pairs
-	}
-	// 3b. map value must represent k:v pairs
-	mm, ok := mv.(map[string]interface{})
-	if !ok {
-		*s = append(*s, key)
+// UnknownJSONKeys is like the package level UnknownJSONKeys function but
+// uses the Checker's settings.
+func (c *Checker) UnknownJSONKeys(b []byte, val interface{}) ([]string, error) {
+	r, err := c.Check(b, val)
+	if err != nil {
+		return nil, err
 	}
+	return r.Unknown, nil
+}
 
-	// 4. Build the map of struct field name:value
-	//    We make every key (field) label look like an exported label - "Fieldname".
-	//    If there is a JSON tag it is used instead of the field label, and saved to
-	//    insure that the spec'd tag matches the JSON key exactly.
-	type fieldSpec struct {
-		val reflect.Value
-		tag string
-	}
-	fieldCnt := val.NumField()
-	fields := make(map[string]*fieldSpec, fieldCnt)
-	for i := 0; i < fieldCnt; i++ {
-		if len(typ.Field(i).PkgPath) > 0 {
-			continue // field is NOT exported
-		}
-		t := typ.Field(i).Tag.Get("json")
-		tags := strings.Split(t, ",")
-		tag := strings.ToLower(tags[0])
-		if tag == "-" {
-			tag = ""
-		}
-		if tag == "" {
-			fields[strings.Title(strings.ToLower(typ.Field(i).Name))] = &fieldSpec{val.Field(i), ""}
-		} else {
-			fields[strings.Title(strings.ToLower(tag))] = &fieldSpec{val.Field(i), tag}
-		}
-	}
+// UnknownJSONKeysWithWarnings is like UnknownJSONKeys but also returns the
+// warnings for the JSON object - see Report.Warnings.  Warnings are findings,
+// like a *DeprecatedKeyWarning for a key that is still decoded but has been
+// replaced, that can be logged without rejecting the JSON object.
+func UnknownJSONKeysWithWarnings(b []byte, val interface{}) ([]string, []error, error) {
+	return std.UnknownJSONKeysWithWarnings(b, val)
+}
 
-	// 5. check that map keys correspond to exported field names
-	var spec *fieldSpec
-	for k, m := range mm {
-		lk := strings.ToLower(k)
-		for _, sk := range skipkeys {
-			if key == "" && lk == sk {
-				goto next
-			} else if key != "" && key+"."+lk == sk {
-				goto next
-			}
-		}
-		// used for !ok and recursion on checkAllFields
-		if key == "" {
-			tkey = lk
-		} else {
-			tkey = key + "." + lk
-		}
-		spec, ok = fields[strings.Title(lk)]
-		if !ok {
-			*s = append(*s, tkey)
-			return nil
-		}
-		if len(spec.tag) > 0 && spec.tag != lk { // JSON key doesn't match Field tag
-			if k == "" {
-				tkey = "[" + spec.tag + "]"
-			} else {
-				tkey = key + ".[" + spec.tag + "]"
-			}
-			*s = append(*s, tkey) // include tag in brackets
-			return nil
-		}
-		_ = checkAllFields(m, spec.val, s, tkey)
-	next:
+// UnknownJSONKeysWithWarnings is like the package level
+// UnknownJSONKeysWithWarnings function but uses the Checker's settings.
+func (c *Checker) UnknownJSONKeysWithWarnings(b []byte, val interface{}) ([]string, []error, error) {
+	r, err := c.Check(b, val)
+	if err != nil {
+		return nil, nil, err
 	}
+	return r.Unknown, r.Warnings, nil
+}
 
-	return nil
+// SetDeprecatedKeys sets a registry of deprecated struct members, in
+// addition to those tagged `checkjson:"deprecated=hint"`.  The map keys are
+// the member paths in the dot-notation of MissingJSONKeys and
+// SetMembersToIgnore - e.g., "tls.cert" - and the values are hints for the
+// replacements - e.g., "use tls.cert_file".  NOTE: the paths are case
+// insensitive.  A JSON key that is decoded to a deprecated member is reported
+// with a *DeprecatedKeyWarning in Report.Warnings - see
+// UnknownJSONKeysWithWarnings - not as an error.  Deprecated members, like
+// those tagged `checkjson:"optional"`, aren't reported as missing.  Calling
+// SetDeprecatedKeys with nil clears the registry.
+//
+// The hint of a "deprecated=" tag option is the rest of the tag, so it can
+// have commas but must be the last option; e.g.,
+// `checkjson:"alias=crt,deprecated=use tls.cert_file, or tls.cert_pem"`.
+//
+// SetDeprecatedKeys configures the default Checker used by the package level
+// functions.  Use NewChecker with the DeprecatedKeys option for a Checker with
+// its own registry.
+func SetDeprecatedKeys(m map[string]string) {
+	DeprecatedKeys(m)(std)
 }
@@ -7,24 +7,45 @@
 // struct member names/tags to see if they will be decoded using encoding/json package.
 //
 // There are several options: Validate returns an error on the first key:value pair
-// that won't decode, and UnknownJSONKeys returns a slice of all the keys that won't be decoded.
+// that won't decode, ValidateAll returns an error for each of them, and UnknownJSONKeys
+// returns a slice of all the keys that won't be decoded.
 // 
 // A complementary function MissingJSONKeys provides a slice of struct members that won't
 // be set by the JSON object using encoding/json.
 //
-// NOTE: this package DOES NOT support recursive struct definitions.
+// Check provides the results of UnknownJSONKeys, MissingJSONKeys and ExistingJSONKeys
+// in a single Report, decoding the JSON object and scanning the struct just once.
+//
+// As with encoding/json, the exported fields of embedded (anonymous) structs are promoted
+// to the struct that embeds them, unless the embedded struct has a JSON tag.
+//
+// For map members - e.g., map[string]Server - the JSON keys must decode to the map key
+// type and the value for each key is checked against the map element type; the map
+// keys are included in the dot-notation paths, "servers.web1.port".
+//
+// Recursive struct definitions - e.g., type Node struct { Children []*Node } - are
+// supported; members are scanned only as deep as the JSON object is nested.
+//
+// Interface members are checked against their dynamic value when it is a non-nil
+// pointer, as encoding/json decodes into it; otherwise against the concrete type
+// given to RegisterInterfaceType, if any.
+//
+// Members that decode themselves - their type implements json.Unmarshaler or
+// encoding.TextUnmarshaler, e.g. time.Time - are not checked, unless the type
+// describes the JSON value it is decoded from by implementing JSONShaper.
+//
+// The JSON object is read token by token as it is compared with the struct, rather
+// than being decoded to a map[string]interface{}.  CheckReader, ValidateReader,
+// ValidateAllReader and Findings read it from an io.Reader, so huge JSON documents
+// can be checked without holding them in memory.
 package checkjson
 
 import (
 	"encoding/json"
-	"fmt"
-	"reflect"
+	"errors"
 	"strings"
 )
 
-// List of JSON keys to NOT validate.
-var skipkeys = []string{"config"}
-
 // SetKeysToIgnore maintains a list of JSON keys that should
 // not be validated as exported struct fields.  By default the
 // JSON key "config" is not validated; it can be removed from
@@ -33,18 +54,27 @@ var skipkeys = []string{"config"}
 // override the default. NOTE: keys are case insensitive - i.e.,
 // "key" == "Key" == "KEY".
 //
+// A key in the list is ignored wherever it occurs in the JSON object.
+// For nested JSON objects a key can also be given using the dot-notation
+// of UnknownJSONKeys - "elem2.notes" - to ignore it just at that location.
+// Dot-notation keys are patterns: "*" matches any one key or array element,
+// "**" any number of them, and "[]" any array element; e.g.,
+// "plugins.*.config", "**.comment" or "servers[].debug".  Array elements
+// can be left out, so "servers.debug" is the same as "servers[].debug".
+// The same patterns are used by SetMembersToIgnore.  Validate and
+// UnknownJSONKeys don't check an ignored key or its value; as encoding/json
+// still decodes it, MissingJSONKeys and ExistingJSONKeys list the member it
+// sets as existing, but not the members of its value.
+//
 // A JSON object key that corresponds with a struct member that is defined
 // with the JSON tag "-" will not be reported, since it is a valid key for
 // the struct definiton, even if it won't be decoded by the Go stdlib.
+//
+// SetKeysToIgnore configures the default Checker used by the package level
+// functions.  Use NewChecker with the KeysToIgnore option for a Checker with
+// its own list of keys.
 func SetKeysToIgnore(s ...string) {
-	if len(s) == 0 {
-		skipkeys = skipkeys[:0] // remove "config"
-		return
-	}
-	skipkeys = make([]string, len(s))
-	for i, v := range s {
-		skipkeys[i] = strings.ToLower(v)
-	}
+	KeysToIgnore(s...)(std)
 }
 
 // Validate scans a JSON object and returns an error when it encounters
@@ -61,149 +91,107 @@ func SetKeysToIgnore(s ...string) {
 // NOTE: result is similar to using (*Decoder)DisallowUnknownFields() in
 // encoding/json lib; however, with Validate() error will provide route for
 // for nested JSON object keys.
+//
+// The error for a JSON object that won't decode is one of the types
+// UnknownKeyError, CaseMismatchError, AliasError, EmbeddedPointerError,
+// TypeMismatchError, NumberError, StringOptionError, NotObjectError,
+// NotArrayError, ArrayLengthError or MapKeyError.  Each has the Path to the
+// JSON key or value and the Go type it was checked against.  They are wrapped
+// with the context of the JSON keys and array elements that lead to the
+// value, so use errors.As to retrieve them:
+//
+//	var uerr *checkjson.UnknownKeyError
+//	if errors.As(err, &uerr) {
+//		fmt.Println("no member for", uerr.Path, "in", uerr.Type)
+//	}
 func Validate(b []byte, val interface{}) error {
-	m := make(map[string]interface{})
-	if err := json.Unmarshal(b, &m); err != nil {
-		return ResolveJSONError(b, err)
-	}
-	if err := checkFields(m, reflect.ValueOf(val)); err != nil {
-		return err
-	}
-	return nil
+	return std.Validate(b, val)
 }
 
-func checkFields(mv interface{}, val reflect.Value) error {
-	// 1. Convert any pointer value.
-	if val.Kind() == reflect.Ptr {
-		val = reflect.Indirect(val) // convert ptr to struc
-	}
-	// zero Value?
-	if !val.IsValid() {
-		return nil
-	}
-	typ := val.Type()
-
-	// json.RawMessage is a []byte/[]uint8 and has Kind() == reflect.Slice
-	if typ.Name() == "RawMessage" {
-		return nil
-	}
-
-	// 2. If its a slice then 'mv' should hold a []interface{} value.
-	//    Loop through the members of 'mv' and see that they are valid relative
-	//    to the <T> of val []<T>.
-	if typ.Kind() == reflect.Slice {
-		tval := typ.Elem()
-		if tval.Kind() == reflect.Ptr {
-			tval = tval.Elem()
-		}
-		// slice may be nil, so create a Value of it's type
-		// 'mv' must be of type []interface{}
-		sval := reflect.New(tval)
-		slice, ok := mv.([]interface{})
-		if !ok {
-			return fmt.Errorf("JSON value not an array")
-		}
-		// 2.1. Check members of JSON array.
-		//      This forces all of them to be regular and w/o typos in key labels.
-		for n, sl := range slice {
-			if err := checkFields(sl, sval); err != nil {
-				return fmt.Errorf("[array element #%d] %s", n+1, err.Error())
-			}
-		}
-		return nil // done with reflect.Slice value
-	}
-
-	// 3a. Ignore anything that's not a struct.
-	if typ.Kind() != reflect.Struct {
-		return nil // just ignore it - don't look for k:v pairs
+// Validate is like the package level Validate function but uses the
+// Checker's settings.
+func (c *Checker) Validate(b []byte, val interface{}) error {
+	w, err := c.walk(b, val)
+	if err != nil {
+		return err
 	}
-	// 3b. map value must represent k:v pairs
-	mm, ok := mv.(map[string]interface{})
-	if !ok {
-		return fmt.Errorf("JSON object does not have k:v pairs for member: %s",
-			typ.Name())
+	if len(w.errs) > 0 {
+		return w.errs[0]
 	}
+	return nil
+}
 
-	// 4. Build the map of struct field name:value
-	//    We make every key (field) label look like an exported label - "Fieldname".
-	//    If there is a JSON tag it is used instead of the field label, and saved to
-	//    insure that the spec'd tag matches the JSON key exactly.
-	type fieldSpec struct {
-		val reflect.Value
-		tag string
-	}
-	fieldCnt := val.NumField()
-	fields := make(map[string]*fieldSpec, fieldCnt)
-	for i := 0; i < fieldCnt; i++ {
-		if len(typ.Field(i).PkgPath) > 0 {
-			continue // field is NOT exported
-		}
-		var tag string
-		t := typ.Field(i).Tag.Get("json")
-		tags := strings.Split(t, ",")
-		tag = strings.ToLower(tags[0])
-		// handle ignore member JSON tag, "-"
-		if tag == "-" {
-			tag = ""
-		}
-		if tag == "" {
-			fields[strings.Title(strings.ToLower(typ.Field(i).Name))] = &fieldSpec{val.Field(i), ""}
-		} else {
-			fields[strings.Title(strings.ToLower(tag))] = &fieldSpec{val.Field(i), tag}
-		}
-	}
+// ValidateAll is like Validate, but rather than stopping at the first
+// key:value pair that will not decode it returns an error for each of
+// them, in the order they occur in the JSON object, joined by errors.Join.
+// (A JSON syntax error is returned by itself.)  To list the errors:
+//
+//	if err := checkjson.ValidateAll(data, &cfg); err != nil {
+//		for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+//			fmt.Println(e)
+//		}
+//	}
+//
+// The individual errors can be retrieved with errors.As, as for Validate.
+func ValidateAll(b []byte, val interface{}) error {
+	return std.ValidateAll(b, val)
+}
 
-	// 5. check that map keys correspond to exported field names
-	var spec *fieldSpec
-	for k, m := range mm {
-		lk := strings.ToLower(k)
-		for _, sk := range skipkeys {
-			if lk == sk {
-				goto next
-			}
-		}
-		spec, ok = fields[strings.Title(lk)]
-		if !ok {
-			return fmt.Errorf("no member for JSON key: %s", k)
-		}
-		if len(spec.tag) > 0 && spec.tag != lk { // JSON key doesn't match Field tag
-			return fmt.Errorf("key: %s -  does not match tag: %s", k, spec.tag)
-		}
-		if err := checkFields(m, spec.val); err != nil { // could be nested structs
-			return fmt.Errorf("checking subkeys of JSON key: %s - %s", k, err.Error())
-		}
-	next:
+// ValidateAll is like the package level ValidateAll function but uses the
+// Checker's settings.
+func (c *Checker) ValidateAll(b []byte, val interface{}) error {
+	w, err := c.walk(b, val)
+	if err != nil {
+		return err
 	}
-
-	return nil
+	return errors.Join(w.errs...)
 }
 
 // ResolveJSONError tries to augment json.Unmarshal syntax errors with
 // the JSON context - key:... - and position when parsing stopped, if possible.
 // (This is useful when errors occur when unmarshaling large JSON objects.)
+// A *json.SyntaxError is returned as a *SyntaxError, with the line and column
+// of the byte offset where parsing stopped and up to 1K bytes of the JSON text
+// before it.
 func ResolveJSONError(data []byte, err error) error {
 	// NOTE: don't need to worry about json.UnmarshalTypeError since all 
 	// unmarshaling is into map[string]interface{}.  In this package we're
 	// (currently) just interested in matching JSON keys with struct members.
 	if e, ok := err.(*json.SyntaxError); ok {
-		// grab stuff ahead of the error
-		var i int
-		var getKey bool
-		for i = int(e.Offset) - 1; i != -1; i-- {
-			switch data[i] {
-			case ':':
-				getKey = true
-			case '\n', '{', '[', ',', ' ':
-				if getKey {
-					goto done
-				}
-			}
+		off := int(e.Offset) - 1 // the offending byte
+		if off < 0 {
+			off = 0
 		}
-	done:
-		info := strings.TrimSpace(string(data[i+1 : int(e.Offset)]))
-		return fmt.Errorf("%s - at: %s (stopped at position: %d)", e.Error(), info, e.Offset)
+		return &SyntaxError{e, errorContext(data[:e.Offset]), newLines(data).position(off)}
 	}
 	// just report all other unmarshal errors
 	return err
 }
 
+// maxContext is the most JSON text that is given as the context of a
+// syntax error.
+const maxContext = 1 << 10
+
+// errorContext returns the JSON text at the end of 'data', back to the last
+// key, for a syntax error after it.
+func errorContext(data []byte) string {
+	if len(data) > maxContext {
+		data = data[len(data)-maxContext:]
+	}
+	// grab stuff ahead of the error
+	var i int
+	var getKey bool
+	for i = len(data) - 1; i != -1; i-- {
+		switch data[i] {
+		case ':':
+			getKey = true
+		case '\n', '{', '[', ',', ' ':
+			if getKey {
+				goto done
+			}
+		}
+	}
+done:
+	return strings.TrimSpace(string(data[i+1:]))
+}
+
@@ -1,6 +1,7 @@
 package checkjson
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 )
@@ -98,3 +99,55 @@ func TestStructIgnoreTag(t *testing.T) {
 		t.Fatalf(err.Error())
 	}
 }
+
+func TestValidateAll(t *testing.T) {
+	fmt.Println("===================== TestValidateAll ...")
+
+	s := new(struct1)
+	data := []byte(`{
+			"this":"is",
+			"is":{"a":"simple","json":"object","else":false},
+			"not":[
+				{"a":"simple"},
+				{"JSON":"object","other":1},
+				"not an object"
+			],
+			"but": {"it":"is a", "little":"goofy"},
+			"else": false}`)
+	err := ValidateAll(data, s)
+	if err == nil {
+		t.Fatal("no error returned")
+	}
+	errs := err.(interface{ Unwrap() []error }).Unwrap()
+	want := []string{
+		"checking subkeys of JSON key: is - no member for JSON key: else",
+		"checking subkeys of JSON key: not - [array element #2] no member for JSON key: other",
+		"checking subkeys of JSON key: not - [array element #3] JSON object does not have k:v pairs for member: struct2",
+		"no member for JSON key: else",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("errs: %d - %v", len(errs), errs)
+	}
+	for i, e := range errs {
+		if e.Error() != want[i] {
+			t.Fatal(e.Error(), "!=", want[i])
+		}
+	}
+	var oerr *NotObjectError
+	if !errors.As(err, &oerr) || oerr.Path.String() != "not.3" {
+		t.Fatalf("NotObjectError: %#v", oerr)
+	}
+	fmt.Println("err ok:", err)
+
+	// Validate always reports the first of them.
+	for i := 0; i < 20; i++ {
+		if err = Validate(data, s); err.Error() != want[0] {
+			t.Fatal(err.Error(), "!=", want[0])
+		}
+	}
+
+	data = []byte(`{"this":"is","is":{"a":"simple","json":"object"}}`)
+	if err = ValidateAll(data, s); err != nil {
+		t.Fatal(err)
+	}
+}